@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+//=====================================================================
+// Function runBatch() - Non interactive parsing of a file or stdin
+//=====================================================================
+// Reads newline delimited entries, skipping blank lines, and writes one
+// result line per entry to stdout. With -list every line may hold several
+// entries. A summary goes to stderr and the exit
+// code is 1 when any entry failed (2 for usage or I/O errors).
+
+//funcid:2200
+func runBatch(args []string) int {
+	var in io.Reader = os.Stdin
+	var entries, failed int
+
+	fs := flag.NewFlagSet("batch", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s batch [flags] [file|-]\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	pf := registerParserFlags(fs)
+	format := fs.String("format", "human", "output `format`: "+strings.Join(outputFormats, ", "))
+	list := fs.Bool("list", false, "each line is a list of selections separated by ',' or ';'")
+	stack := fs.Bool("stack", false, "human format: show the developer error stack instead of the user message")
+	fs.Parse(args)
+
+	parser, err := pf.parser()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	messages, err := pf.messages()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return 2
+	}
+	if fs.NArg() == 1 && fs.Arg(0) != "-" {
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		defer f.Close()
+		in = f
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	results, err := newResultWriter(*format, out, *stack)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		inputStr := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(inputStr) == "" {
+			continue
+		}
+
+		for _, entry := range parseLine(parser, inputStr, *list) {
+			entries++
+			if entry.Err != nil {
+				failed++
+			}
+			if err := results.Write(newResult(entry.Input, entry.Selection, entry.Err, messages.def)); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 2
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		results.Flush()
+		out.Flush()
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	if err := results.Flush(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	out.Flush()
+	fmt.Fprintf(os.Stderr, "%d entries, %d parsed, %d failed\n", entries, entries-failed, failed)
+	if failed > 0 {
+		return 1
+	}
+	return 0
+} // runBatch
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Runs a command with stdin, capturing its stdout and stderr. Commands
+// write to os.Stdout and os.Stderr, so tests using it must not be parallel.
+func runCommand(t *testing.T, run func([]string) int, stdin string, args ...string) (code int, stdout, stderr string) {
+	dir := t.TempDir()
+	files := make([]*os.File, 3)
+	for i, name := range []string{"stdin", "stdout", "stderr"} {
+		f, err := os.Create(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		files[i] = f
+	}
+	if _, err := files[0].WriteString(stdin); err != nil {
+		t.Fatal(err)
+	}
+	files[0].Seek(0, 0)
+
+	saved := []*os.File{os.Stdin, os.Stdout, os.Stderr}
+	os.Stdin, os.Stdout, os.Stderr = files[0], files[1], files[2]
+	code = run(args)
+	os.Stdin, os.Stdout, os.Stderr = saved[0], saved[1], saved[2]
+
+	out, _ := os.ReadFile(files[1].Name())
+	errOut, _ := os.ReadFile(files[2].Name())
+	return code, string(out), string(errOut)
+}
+
+func TestBatch(t *testing.T) {
+	tests := []struct {
+		stdin   string
+		args    []string
+		code    int
+		lines   int
+		summary string
+	}{
+		{"CS 111 Fall 2016\n\nMATH-220 S20\r\n", nil, 0, 2, "2 entries, 2 parsed, 0 failed"},
+		{"CS 111 Fall 2016\nCS 111 Fxll 2016\n  \nCS#111 Fall 2016\n", nil, 1, 3, "3 entries, 1 parsed, 2 failed"},
+		{"", nil, 0, 0, "0 entries, 0 parsed, 0 failed"},
+		{"CS 111 Fall 2016, MATH 220 Fxll 2020\n", []string{"-list"}, 1, 2, "2 entries, 1 parsed, 1 failed"},
+		{"CS 111 Fall 2016\n", []string{"-format", "csv"}, 0, 2, "1 entries, 1 parsed, 0 failed"},
+	}
+	for _, test := range tests {
+		args := append([]string{"-earliest", "2010", "-latest", "2030"}, test.args...)
+		code, stdout, stderr := runCommand(t, runBatch, test.stdin, args...)
+		if code != test.code {
+			t.Errorf("%q %q: exit code %d, want %d", test.stdin, test.args, code, test.code)
+		}
+		if lines := strings.Count(stdout, "\n"); lines != test.lines {
+			t.Errorf("%q %q: %d output lines, want %d:\n%s", test.stdin, test.args, lines, test.lines, stdout)
+		}
+		if strings.TrimSpace(stderr) != test.summary {
+			t.Errorf("%q %q: summary %q, want %q", test.stdin, test.args, stderr, test.summary)
+		}
+	}
+}
+
+func TestBatchFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "entries.txt")
+	if err := os.WriteFile(file, []byte("CS 111 Fall 2016\nCS 111 2016 Fall\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	code, stdout, _ := runCommand(t, runBatch, "", "-earliest", "2010", "-latest", "2030", file)
+	if code != 0 || stdout != "CS 111 Fall 2016 |==> [CS 111 2016 Fall]\nCS 111 2016 Fall |==> [CS 111 2016 Fall]\n" {
+		t.Errorf("%s: exit code %d, output:\n%s", file, code, stdout)
+	}
+
+	// Usage and I/O errors exit with 2 and no summary
+	for _, args := range [][]string{
+		{filepath.Join(t.TempDir(), "missing.txt")},
+		{file, file},
+		{"-format", "xml", file},
+		{"-fieldsep", "ab", file},
+	} {
+		code, _, stderr := runCommand(t, runBatch, "", args...)
+		if code != 2 || strings.Contains(stderr, "entries,") {
+			t.Errorf("%q: exit code %d, stderr %q, want 2 and no summary", args, code, stderr)
+		}
+	}
+}
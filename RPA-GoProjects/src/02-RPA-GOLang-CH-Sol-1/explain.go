@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"courseparser"
+)
+
+//=====================================================================
+// Function runExplain() - Parse tree of each entry, for support staff
+//=====================================================================
+// Explains the entries given as arguments, or the newline delimited entries
+// of stdin, as an indented tree (or JSON, one object per entry) showing each
+// rule run, the span it consumed, the token it produced and, with -events,
+// the funcid trace records inside it. A failed entry also shows the message
+// the student sees (see -lang). The exit code is 1 when any entry failed (2
+// for usage or I/O errors).
+
+//funcid:2400
+func runExplain(args []string) int {
+	var failed int
+
+	fs := flag.NewFlagSet("explain", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s explain [flags] [entry ...]\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	pf := registerParserFlags(fs)
+	format := fs.String("format", "tree", "output `format`: tree, json")
+	events := fs.Bool("events", true, "show the funcid trace records of each step in the tree")
+	fs.Parse(args)
+
+	parser, err := pf.parser()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	messages, err := pf.messages()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if *format != "tree" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "unknown explain format %q (expecting tree or json)\n", *format)
+		return 2
+	}
+
+	inputs := fs.Args()
+	if len(inputs) == 0 {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if line := strings.TrimRight(scanner.Text(), "\r"); strings.TrimSpace(line) != "" {
+				inputs = append(inputs, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	enc := json.NewEncoder(out)
+	enc.SetEscapeHTML(false)
+	for _, input := range inputs {
+		root, _, err := parser.Explain(input)
+		if err != nil {
+			failed++
+		}
+
+		if *format == "json" {
+			if err := enc.Encode(explained{root, messages.def.Message(err)}); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 2
+			}
+			continue
+		}
+		writeStepTree(out, root, 0, *events)
+		if err != nil {
+			fmt.Fprintf(out, "User message: %s\n", messages.def.Message(err))
+		}
+		fmt.Fprintln(out)
+	}
+
+	if failed > 0 {
+		return 1
+	}
+	return 0
+} // runExplain
+
+// JSON object of an explained entry, the root step and the user message
+type explained struct {
+	*courseparser.Step
+	UserMessage string `json:"user_message,omitempty"`
+}
+
+// Writes a step and its sub steps, indented by depth
+//
+//	[Semester] 7:11 "Fxll"  FAILED 975.15 - Invalid Semester lookup FXLL
+//	  - 950.10 IN- : getSemesterToken() @7
+func writeStepTree(w io.Writer, step *courseparser.Step, depth int, events bool) {
+	indent := strings.Repeat("  ", depth)
+
+	fmt.Fprintf(w, "%s%s %d:%d %q", indent, step.Name, step.Start, step.End, step.Span)
+	if step.Token != "" {
+		fmt.Fprintf(w, " => %s", step.Token)
+	}
+	if !step.OK() {
+		fmt.Fprintf(w, "  FAILED %s", step.Error)
+	}
+	fmt.Fprintln(w)
+
+	if events {
+		for _, ev := range step.Events {
+			fmt.Fprintf(w, "%s  - %s %s", indent, ev.Funcid, ev.Msg)
+			if ev.Offset >= 0 {
+				fmt.Fprintf(w, " @%d", ev.Offset)
+			}
+			keys := make([]string, 0, len(ev.Attrs))
+			for key := range ev.Attrs {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				fmt.Fprintf(w, " %s=%q", key, ev.Attrs[key])
+			}
+			fmt.Fprintln(w)
+		}
+	}
+
+	for _, sub := range step.Steps {
+		writeStepTree(w, sub, depth+1, events)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"courseparser"
+)
+
+func TestExplainTree(t *testing.T) {
+	code, stdout, _ := runCommand(t, runExplain, "", "-earliest", "2010", "-latest", "2030", "-events=false", "CS 111 Fall 2019")
+	want := `Parse() 0:16 "CS 111 Fall 2019" => CS 111 2019 Fall
+  layout course-session 0:16 "CS 111 Fall 2019"
+    [DeptCourse] 0:6 "CS 111"
+      [Dept] 0:2 "CS" => CS
+      Delimiter 2:3 " "
+      [Course] 3:6 "111" => 111
+    Field Separator 6:7 " "
+    [OfferSession] 7:16 "Fall 2019"
+      [TermOrRange] 7:16 "Fall 2019"
+        [Term] 7:16 "Fall 2019"
+          [Semester-Year] 7:16 "Fall 2019"
+            [Semester] 7:11 "Fall" => Fall
+            Delimiter 11:12 " "
+            [Year] 12:16 "2019" => 2019
+
+`
+	if code != 0 || stdout != want {
+		t.Errorf("exit code %d, tree:\n%s\nwant:\n%s", code, stdout, want)
+	}
+
+	code, stdout, _ = runCommand(t, runExplain, "", "-earliest", "2010", "-latest", "2030", "CS 111 Fxll 2019")
+	if code != 1 || !strings.Contains(stdout, `[Semester] 7:11 "Fxll"  FAILED 975.15 - `) || !strings.Contains(stdout, "  - 975.10 IN- : validateSemester() token=\"FXLL\"") {
+		t.Errorf("exit code %d, tree:\n%s", code, stdout)
+	}
+	if !strings.HasSuffix(stdout, "\nUser message: 'Fxll' is not a semester. Did you mean Fall?\n\n") {
+		t.Errorf("tree without the user message:\n%s", stdout)
+	}
+
+	_, stdout, _ = runCommand(t, runExplain, "", "-earliest", "2010", "-latest", "2030", "-lang", "es", "CS 111 Fxll 2019")
+	if !strings.HasSuffix(stdout, "\nUser message: 'Fxll' no es un semestre. ¿Quiso decir Fall?\n\n") {
+		t.Errorf("-lang es: tree without the Spanish user message:\n%s", stdout)
+	}
+	if code, _, _ := runCommand(t, runExplain, "", "-lang", "fr", "CS 111 Fall 2019"); code != 2 {
+		t.Errorf("-lang fr: exit code %d, want 2", code)
+	}
+}
+
+func TestExplainJSON(t *testing.T) {
+	stdin := "CS 111 Fall 2019\n\nCS 111 Fxll 2019\n"
+	code, stdout, _ := runCommand(t, runExplain, stdin, "-earliest", "2010", "-latest", "2030", "-format", "json")
+	if code != 1 {
+		t.Errorf("exit code %d, want 1", code)
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("%d lines, want one per entry:\n%s", len(lines), stdout)
+	}
+	for i, want := range []struct{ code, message string }{
+		{"", ""},
+		{"975.15 - ", "'Fxll' is not a semester. Did you mean Fall?"},
+	} {
+		var root struct {
+			courseparser.Step
+			UserMessage string `json:"user_message"`
+		}
+		if err := json.Unmarshal([]byte(lines[i]), &root); err != nil {
+			t.Fatal(err)
+		}
+		if root.Name != "Parse()" || len(root.Steps) == 0 || !strings.HasPrefix(root.Error, want.code) || (want.code == "") != root.OK() || root.UserMessage != want.message {
+			t.Errorf("entry %d: %s", i, lines[i])
+		}
+	}
+
+	if code, _, _ := runCommand(t, runExplain, "", "-format", "xml", "CS 111 Fall 2019"); code != 2 {
+		t.Errorf("-format xml: exit code %d, want 2", code)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"testing"
+	"time"
+)
+
+// Parser configuration flags parsed from args
+func testFlags(t *testing.T, args ...string) *parserFlags {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	pf := registerParserFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return pf
+}
+
+// A single -earliest or -latest takes the other bound from -back/-ahead
+func TestYearWindowFlags(t *testing.T) {
+	year := time.Now().Year()
+
+	tests := []struct {
+		args             []string
+		earliest, latest int
+	}{
+		{nil, year - 15, year + 2},
+		{[]string{"-back", "5", "-ahead", "1"}, year - 5, year + 1},
+		{[]string{"-earliest", "2015", "-latest", "2020"}, 2015, 2020},
+		{[]string{"-earliest", "2015"}, 2015, year + 2},
+		{[]string{"-earliest", "2015", "-ahead", "4"}, 2015, year + 4},
+		{[]string{"-latest", "2030"}, year - 15, 2030},
+		{[]string{"-latest", "2030", "-back", "3"}, year - 3, 2030},
+	}
+	for _, test := range tests {
+		window := testFlags(t, test.args...).yearWindow()
+		if earliest, latest := window.Bounds(); earliest != test.earliest || latest != test.latest {
+			t.Errorf("%q: window %d - %d, want %d - %d", test.args, earliest, latest, test.earliest, test.latest)
+		}
+	}
+
+	parser, err := testFlags(t, "-earliest", "2015").parser()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parser.Parse("CS 111 Fall 2016"); err != nil {
+		t.Errorf("-earliest 2015: Parse: %v", err)
+	}
+}
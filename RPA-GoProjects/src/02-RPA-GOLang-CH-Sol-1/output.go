@@ -0,0 +1,269 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"courseparser"
+)
+
+//=====================================================================
+// Output Formats for parsed Course Selections
+//   human : input |==> [CS 111 2016 Fall]  (the REPL / batch layout)
+//   json  : one JSON object per line with named fields and error details
+//   csv   : header row, then one record per entry
+//=====================================================================
+
+// One parsed entry, as written by every output format
+type result struct {
+	Input    string       `json:"input"`
+	OK       bool         `json:"ok"`
+	Dept     string       `json:"dept"`
+	Course   string       `json:"course"`
+	Suffix   string       `json:"suffix,omitempty"`
+	Section  string       `json:"section,omitempty"`
+	Semester string       `json:"semester"`
+	Locale   string       `json:"locale,omitempty"` // locale the semester was named in, with -locale
+	Year     int          `json:"year,omitempty"`
+	Range    *termRange   `json:"range,omitempty"`     // set for a term range, Semester and Year are its start
+	TermCode string       `json:"term_code,omitempty"` // institutional term code, with -termcode
+	Layout   string       `json:"layout,omitempty"`    // input layout matched, e.g. "session-course"
+	Error    *errorDetail `json:"error,omitempty"`
+
+	Corrections []courseparser.Correction `json:"corrections,omitempty"`
+	Confidence  float64                   `json:"confidence,omitempty"` // set when the input had several readings
+
+	tokens []string
+	stack  string
+}
+
+// Term range of an entry, e.g. "AY 2019-20"
+type termRange struct {
+	Start string   `json:"start"` // e.g. "Fall 2019"
+	End   string   `json:"end"`
+	Terms []string `json:"terms"`           // every term of the range, in calendar order
+	Codes []string `json:"codes,omitempty"` // term codes of Terms, with -termcode
+}
+
+// Error details of a failed entry
+type errorDetail struct {
+	Code    string   `json:"code"`           // funcid code of the most specific error
+	Kind    string   `json:"kind,omitempty"` // failure kind, e.g. "invalid semester"
+	Offset  int      `json:"offset"`         // rune position of the failure in the input, -1 if unknown
+	Column  int      `json:"column"`         // terminal column of the failure in the input
+	Char    string   `json:"char,omitempty"` // offending character
+	Message string   `json:"message"`        // message of the most specific error
+	Stack   []string `json:"stack"`          // the whole "ERROR-xxx.yy - ..." stack, outermost first
+
+	UserMessage string `json:"user_message"` // the failure explained to the student, see -lang
+
+	Suggestions []string `json:"suggestions,omitempty"` // "did you mean" fixes for the offending token, best first
+	Candidates  []string `json:"candidates,omitempty"`  // readings of an ambiguous input, best first
+
+	Diagnostics []*errorDetail `json:"diagnostics,omitempty"` // every problem found, with -recover
+	Token       string         `json:"token,omitempty"`       // token a diagnostic is about, e.g. "[Year]"
+}
+
+func newResult(input string, sel courseparser.CourseSelection, err error, messages *courseparser.Messages) result {
+	r := result{
+		Input:    input,
+		OK:       err == nil,
+		Dept:     sel.Dept,
+		Course:   sel.Course,
+		Suffix:   sel.Suffix,
+		Section:  sel.Section,
+		Semester: sel.Semester,
+		Locale:   sel.Locale,
+		Year:     sel.Year,
+		Layout:   sel.Layout,
+		TermCode: sel.TermCode,
+		tokens:   sel.Tokens(),
+
+		Corrections: sel.Corrections,
+	}
+	if sel.Confidence < 1 {
+		r.Confidence = sel.Confidence
+	}
+	if sel.Range != nil {
+		r.Range = &termRange{Start: sel.Range.Start.String(), End: sel.Range.End.String(), Codes: sel.Range.Codes}
+		for _, term := range sel.Range.Terms {
+			r.Range.Terms = append(r.Range.Terms, term.String())
+		}
+		r.tokens = append(r.tokens, "-", sel.Range.End.String())
+	}
+	if sel.TermCode != "" {
+		r.tokens = append(r.tokens, sel.TermCode)
+	}
+	if err != nil {
+		r.Error = newErrorDetail(err, messages)
+		r.stack = err.Error()
+	}
+	return r
+}
+
+func newErrorDetail(err error, messages *courseparser.Messages) *errorDetail {
+	var deepest *courseparser.ParseError
+
+	detail := &errorDetail{Offset: -1, Message: err.Error(), UserMessage: messages.Message(err)}
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		pe, ok := e.(*courseparser.ParseError)
+		if !ok {
+			continue
+		}
+		deepest = pe
+		detail.Stack = append(detail.Stack, strings.TrimSpace(strings.SplitN(pe.Error(), "\n", 2)[0]))
+		if pe.Kind != nil {
+			detail.Kind = pe.Kind.Error()
+		}
+	}
+	if deepest != nil {
+		detail.Code = deepest.Code
+		detail.Message = strings.TrimSpace(deepest.Msg)
+	}
+	for _, suggestion := range courseparser.Suggestions(err) {
+		detail.Suggestions = append(detail.Suggestions, suggestion.Text)
+	}
+	if deepest != nil {
+		for _, d := range deepest.Diagnostics {
+			diag := newErrorDetail(d.Err, messages)
+			if d.Token != courseparser.NoToken {
+				diag.Token = d.Token.String()
+			}
+			detail.Diagnostics = append(detail.Diagnostics, diag)
+		}
+		for _, c := range deepest.Candidates {
+			var tokens []string
+			for _, token := range c.Selection.Tokens() {
+				if token == "" {
+					token = "?"
+				}
+				tokens = append(tokens, token)
+			}
+			detail.Candidates = append(detail.Candidates, strings.Join(tokens, " ")+" ("+c.Reading+")")
+		}
+	}
+	if pe := courseparser.Locate(err); pe != nil {
+		detail.Offset, detail.Column = pe.Offset, pe.Column
+		if pe.Char != 0 {
+			detail.Char = string(pe.Char)
+		}
+	}
+	return detail
+}
+
+// Writes results in one output format
+type resultWriter interface {
+	Write(r result) error
+	Flush() error
+}
+
+var outputFormats = []string{"human", "json", "csv"}
+
+// stack shows the developer error stack rather than the user message in the human format
+func newResultWriter(format string, w io.Writer, stack bool) (resultWriter, error) {
+	switch format {
+	case "human":
+		return &humanWriter{w: w, stack: stack}, nil
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		return &jsonWriter{enc: enc}, nil
+	case "csv":
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	}
+	return nil, fmt.Errorf("unknown output format %q (expecting one of %s)", format, strings.Join(outputFormats, ", "))
+}
+
+type humanWriter struct {
+	w     io.Writer
+	stack bool
+}
+
+func (hw *humanWriter) Write(r result) error {
+	var err error
+
+	if r.OK {
+		_, err = fmt.Fprintf(hw.w, "%v |==> %v\n", r.Input, r.tokens)
+		return err
+	}
+
+	msg := r.Error.UserMessage
+	if hw.stack {
+		msg = oneLine(r.stack)
+	}
+	if len(r.Error.Diagnostics) > 0 {
+		// A recovered entry, the valid tokens and every problem found
+		_, err = fmt.Fprintf(hw.w, "%v |==> %v %v\n", r.Input, r.tokens, msg)
+	} else {
+		_, err = fmt.Fprintf(hw.w, "%v |==> %v\n", r.Input, msg)
+	}
+	return err
+}
+
+func (hw *humanWriter) Flush() error {
+	return nil
+}
+
+type jsonWriter struct {
+	enc *json.Encoder
+}
+
+func (jw *jsonWriter) Write(r result) error {
+	return jw.enc.Encode(r)
+}
+
+func (jw *jsonWriter) Flush() error {
+	return nil
+}
+
+type csvWriter struct {
+	w      *csv.Writer
+	header bool
+}
+
+var csvHeader = []string{"input", "ok", "dept", "course", "suffix", "section", "semester", "year", "range_start", "range_end", "error_code", "error_offset", "error_message", "user_message", "suggestions", "term_code"}
+
+func (cw *csvWriter) Write(r result) error {
+	var year, rangeStart, rangeEnd, code, offset, message, userMessage, suggestions string
+
+	if !cw.header {
+		cw.header = true
+		if err := cw.w.Write(csvHeader); err != nil {
+			return err
+		}
+	}
+
+	if r.Year != 0 {
+		year = strconv.Itoa(r.Year)
+	}
+	if r.Range != nil {
+		rangeStart, rangeEnd = r.Range.Start, r.Range.End
+	}
+	if r.Error != nil {
+		code, offset, message, userMessage = r.Error.Code, strconv.Itoa(r.Error.Offset), r.Error.Message, r.Error.UserMessage
+		suggestions = strings.Join(r.Error.Suggestions, ";")
+	}
+	return cw.w.Write([]string{r.Input, strconv.FormatBool(r.OK), r.Dept, r.Course, r.Suffix, r.Section, r.Semester, year, rangeStart, rangeEnd, code, offset, message, userMessage, suggestions, r.TermCode})
+}
+
+func (cw *csvWriter) Flush() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// Flattens a multi line error stack into "ERROR-a - ... | ERROR-b - ..."
+func oneLine(stack string) string {
+	var levels []string
+
+	for _, level := range strings.Split(stack, "\n") {
+		if level = strings.TrimSpace(level); level != "" {
+			levels = append(levels, level)
+		}
+	}
+	return strings.Join(levels, " | ")
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"courseparser"
+)
+
+// Results of inputs parsed by a Parser with a fixed year window
+func testResults(t *testing.T, inputs ...string) []result {
+	parser, err := testFlags(t, "-earliest", "2010", "-latest", "2030").parser()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var results []result
+	for _, input := range inputs {
+		sel, err := parser.Parse(input)
+		results = append(results, newResult(input, sel, err, nil))
+	}
+	return results
+}
+
+// Writes results in format
+func writeResults(t *testing.T, format string, stack bool, results []result) string {
+	var buf bytes.Buffer
+
+	w, err := newResultWriter(format, &buf, stack)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		if err := w.Write(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestHumanOutput(t *testing.T) {
+	results := testResults(t, "CS-111 Fall 2016", "CS 111 Fxll 2016")
+
+	lines := strings.Split(writeResults(t, "human", false, results), "\n")
+	if lines[0] != "CS-111 Fall 2016 |==> [CS 111 2016 Fall]" {
+		t.Errorf("parsed line %q", lines[0])
+	}
+	if want := "CS 111 Fxll 2016 |==> " + results[1].Error.UserMessage; lines[1] != want {
+		t.Errorf("failed line %q, want %q", lines[1], want)
+	}
+
+	lines = strings.Split(writeResults(t, "human", true, results), "\n")
+	if !strings.HasPrefix(lines[1], "CS 111 Fxll 2016 |==> ERROR-") || !strings.Contains(lines[1], " | ERROR-950.35 - ") {
+		t.Errorf("-stack line %q, want the one line stack", lines[1])
+	}
+}
+
+func TestJSONOutput(t *testing.T) {
+	results := testResults(t, "CS-111 Fall 2016", "CS 111 Fxll 2016")
+
+	lines := strings.Split(strings.TrimSpace(writeResults(t, "json", false, results)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("%d lines, want one per result", len(lines))
+	}
+
+	var ok, failed map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &ok); err != nil {
+		t.Fatal(err)
+	}
+	if ok["ok"] != true || ok["dept"] != "CS" || ok["course"] != "111" || ok["semester"] != "Fall" || ok["year"] != 2016.0 || ok["error"] != nil {
+		t.Errorf("parsed entry %s", lines[0])
+	}
+
+	if err := json.Unmarshal([]byte(lines[1]), &failed); err != nil {
+		t.Fatal(err)
+	}
+	detail, _ := failed["error"].(map[string]interface{})
+	if failed["ok"] != false || detail == nil {
+		t.Fatalf("failed entry %s", lines[1])
+	}
+	if detail["code"] != "975.15" || detail["kind"] != courseparser.ErrInvalidSemester.Error() || detail["offset"] != 7.0 || detail["column"] != 7.0 {
+		t.Errorf("error details %s", lines[1])
+	}
+	if stack, _ := detail["stack"].([]interface{}); len(stack) < 2 {
+		t.Errorf("error stack %v, want every level", detail["stack"])
+	}
+	if suggestions, _ := detail["suggestions"].([]interface{}); len(suggestions) == 0 || suggestions[0] != "Fall" {
+		t.Errorf("suggestions %v, want Fall first", detail["suggestions"])
+	}
+}
+
+func TestCSVOutput(t *testing.T) {
+	results := testResults(t, "CS-111 Fall 2016", "CS 111 Fxll 2016", `"CS, 111" Fall`, "CS 111 AY 2019-20")
+
+	records, err := csv.NewReader(strings.NewReader(writeResults(t, "csv", false, results))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 5 || strings.Join(records[0], ",") != strings.Join(csvHeader, ",") {
+		t.Fatalf("records %q, want the header and one per result", records)
+	}
+
+	column := make(map[string]int)
+	for i, name := range csvHeader {
+		column[name] = i
+	}
+	for _, test := range []struct {
+		record      []string
+		field, want string
+	}{
+		{records[1], "ok", "true"},
+		{records[1], "dept", "CS"},
+		{records[1], "year", "2016"},
+		{records[1], "error_code", ""},
+		{records[2], "ok", "false"},
+		{records[2], "year", ""},
+		{records[2], "error_code", "975.15"},
+		{records[2], "error_offset", "7"},
+		{records[2], "user_message", "'Fxll' is not a semester. Did you mean Fall?"},
+		{records[2], "suggestions", "Fall"},
+		{records[1], "user_message", ""},
+		{records[3], "input", `"CS, 111" Fall`},
+		{records[3], "year", ""},
+		{records[1], "range_start", ""},
+		{records[4], "year", "2019"},
+		{records[4], "range_start", "Fall 2019"},
+		{records[4], "range_end", "Summer 2020"},
+	} {
+		if got := test.record[column[test.field]]; got != test.want {
+			t.Errorf("%q %s = %q, want %q", test.record[0], test.field, got, test.want)
+		}
+	}
+}
+
+func TestUnknownFormat(t *testing.T) {
+	if _, err := newResultWriter("xml", &bytes.Buffer{}, false); err == nil {
+		t.Error("newResultWriter(xml): no error")
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"courseparser"
+)
+
+//=====================================================================
+// HTTP JSON API around Parser.Parse()
+//   POST /parse        {"input": "CS-111 Fall 2016"}        => result
+//   POST /parse/batch  {"inputs": ["CS111 2016 Fall", ...]}  => {"results": [...], ...}
+//   POST /parse/list   {"input": "CS111 Fall 2019, MATH 220 S20"} => {"results": [...], ...}
+// A single entry that fails to parse is answered with 422 and the same
+// result body (ok=false plus the positional error details). The user
+// messages are in the first language of the Accept-Language header we
+// have a catalog for, else in the -lang one. A request may name the
+// "locale" of its semester names (es, de, fr or auto), else -locale applies.
+//=====================================================================
+
+// Request limits
+const maxRequestBytes = 1 << 20
+const maxBatchInputs = 10000
+
+// Connection timeouts, so a slow or idle client cannot hold a connection open
+const readHeaderTimeout = 5 * time.Second
+const readTimeout = 30 * time.Second
+const writeTimeout = 60 * time.Second
+const idleTimeout = 2 * time.Minute
+
+type parseRequest struct {
+	Input  *string `json:"input"`
+	Locale string  `json:"locale,omitempty"` // semester names locale of the input, else the -locale one
+}
+
+type batchRequest struct {
+	Inputs []string `json:"inputs"`
+	Locale string   `json:"locale,omitempty"`
+}
+
+type batchResponse struct {
+	Results []result `json:"results"`
+	Parsed  int      `json:"parsed"`
+	Failed  int      `json:"failed"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+//funcid:2300
+func runServe(args []string) int {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	pf := registerParserFlags(fs)
+	addr := fs.String("addr", ":8080", "listen `address`")
+	fs.Parse(args)
+
+	parser, err := pf.parser()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	messages, err := pf.messages()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           newServer(parser, messages),
+		ReadHeaderTimeout: readHeaderTimeout,
+		ReadTimeout:       readTimeout,
+		WriteTimeout:      writeTimeout,
+		IdleTimeout:       idleTimeout,
+	}
+
+	fmt.Fprintf(os.Stderr, "Course Selection API listening on %v\n", *addr)
+	if err := srv.ListenAndServe(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+} // runServe
+
+// newServer returns the API handler. The Parser is shared by every request.
+func newServer(parser *courseparser.Parser, messages *messageSet) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/parse", func(w http.ResponseWriter, r *http.Request) {
+		var req parseRequest
+
+		if !decodeRequest(w, r, &req) {
+			return
+		}
+		if req.Input == nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: `missing "input"`})
+			return
+		}
+
+		parser, ok := localeParser(w, parser, req.Locale)
+		if !ok {
+			return
+		}
+		msgs := messages.negotiate(r.Header.Get("Accept-Language"))
+		sel, err := parser.Parse(*req.Input)
+		if err != nil {
+			writeJSON(w, http.StatusUnprocessableEntity, newResult(*req.Input, sel, err, msgs))
+			return
+		}
+		writeJSON(w, http.StatusOK, newResult(*req.Input, sel, nil, msgs))
+	})
+
+	mux.HandleFunc("/parse/list", func(w http.ResponseWriter, r *http.Request) {
+		var req parseRequest
+
+		if !decodeRequest(w, r, &req) {
+			return
+		}
+		if req.Input == nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: `missing "input"`})
+			return
+		}
+
+		parser, ok := localeParser(w, parser, req.Locale)
+		if !ok {
+			return
+		}
+		msgs := messages.negotiate(r.Header.Get("Accept-Language"))
+		resp := batchResponse{Results: []result{}}
+		for _, entry := range parser.ParseList(*req.Input) {
+			if entry.Err != nil {
+				resp.Failed++
+			} else {
+				resp.Parsed++
+			}
+			resp.Results = append(resp.Results, newResult(entry.Input, entry.Selection, entry.Err, msgs))
+		}
+		writeJSON(w, http.StatusOK, resp)
+	})
+
+	mux.HandleFunc("/parse/batch", func(w http.ResponseWriter, r *http.Request) {
+		var req batchRequest
+
+		if !decodeRequest(w, r, &req) {
+			return
+		}
+		if len(req.Inputs) > maxBatchInputs {
+			writeJSON(w, http.StatusRequestEntityTooLarge, errorResponse{Error: fmt.Sprintf("at most %d inputs per batch", maxBatchInputs)})
+			return
+		}
+
+		parser, ok := localeParser(w, parser, req.Locale)
+		if !ok {
+			return
+		}
+		msgs := messages.negotiate(r.Header.Get("Accept-Language"))
+		resp := batchResponse{Results: make([]result, 0, len(req.Inputs))}
+		for _, input := range req.Inputs {
+			sel, err := parser.Parse(input)
+			if err != nil {
+				resp.Failed++
+			} else {
+				resp.Parsed++
+			}
+			resp.Results = append(resp.Results, newResult(input, sel, err, msgs))
+		}
+		writeJSON(w, http.StatusOK, resp)
+	})
+
+	return mux
+}
+
+// Parser of a request "locale", answering 400 when there is no such locale
+func localeParser(w http.ResponseWriter, parser *courseparser.Parser, locale string) (*courseparser.Parser, bool) {
+	if locale == "" {
+		return parser, true
+	}
+	if err := courseparser.CheckLocale(locale); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return nil, false
+	}
+	return parser.WithLocale(locale), true
+}
+
+// Decodes a JSON POST body into v, answering 4xx when the request is not one
+func decodeRequest(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Error: "expecting POST"})
+		return false
+	}
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "invalid request body: " + err.Error()})
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.Encode(v)
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// API server with a fixed year window, configured like "serve" from args
+func testServer(t *testing.T, args ...string) *httptest.Server {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	pf := registerParserFlags(fs)
+	if err := fs.Parse(append([]string{"-earliest", "2010", "-latest", "2030"}, args...)); err != nil {
+		t.Fatal(err)
+	}
+	parser, err := pf.parser()
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages, err := pf.messages()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(newServer(parser, messages))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// POSTs body to path and decodes the JSON answer into v
+func post(t *testing.T, srv *httptest.Server, path string, body string, header map[string]string, v interface{}) int {
+	req, err := http.NewRequest(http.MethodPost, srv.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, value := range header {
+		req.Header.Set(key, value)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("POST %s: Content-Type %q", path, ct)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatalf("POST %s: decoding the answer: %v", path, err)
+	}
+	return resp.StatusCode
+}
+
+func TestParse(t *testing.T) {
+	srv := testServer(t)
+
+	var ok result
+	if status := post(t, srv, "/parse", `{"input": "CS-111 Fall 2016"}`, nil, &ok); status != http.StatusOK {
+		t.Fatalf("status %d, want 200", status)
+	}
+	if !ok.OK || ok.Dept != "CS" || ok.Course != "111" || ok.Semester != "Fall" || ok.Year != 2016 || ok.Error != nil {
+		t.Errorf("result %+v", ok)
+	}
+
+	var failed result
+	if status := post(t, srv, "/parse", `{"input": "CS 111 Fxll 2016"}`, nil, &failed); status != http.StatusUnprocessableEntity {
+		t.Fatalf("status %d, want 422", status)
+	}
+	if failed.OK || failed.Error == nil {
+		t.Fatalf("result %+v, want an error", failed)
+	}
+	e := failed.Error
+	if e.Code != "975.15" || e.Offset != 7 || e.Column != 7 || e.Char != "F" || len(e.Suggestions) != 1 || e.Suggestions[0] != "Fall" {
+		t.Errorf("error %+v", e)
+	}
+	if e.UserMessage != "'Fxll' is not a semester. Did you mean Fall?" {
+		t.Errorf("user message %q", e.UserMessage)
+	}
+}
+
+func TestParseBatch(t *testing.T) {
+	srv := testServer(t)
+
+	var resp batchResponse
+	body := `{"inputs": ["CS111 2016 Fall", "CS 111 Fall 1990", "MATH 220 S20", ""]}`
+	if status := post(t, srv, "/parse/batch", body, nil, &resp); status != http.StatusOK {
+		t.Fatalf("status %d, want 200", status)
+	}
+	if resp.Parsed != 2 || resp.Failed != 2 || len(resp.Results) != 4 {
+		t.Fatalf("parsed %d, failed %d, %d results", resp.Parsed, resp.Failed, len(resp.Results))
+	}
+	for i, ok := range []bool{true, false, true, false} {
+		if resp.Results[i].OK != ok {
+			t.Errorf("result %d: ok %v, want %v", i, resp.Results[i].OK, ok)
+		}
+	}
+	if code := resp.Results[1].Error.Code; code != "970.70" {
+		t.Errorf("result 1: code %s, want 970.70", code)
+	}
+}
+
+func TestParseList(t *testing.T) {
+	srv := testServer(t)
+
+	var resp batchResponse
+	if status := post(t, srv, "/parse/list", `{"input": "CS 111, MATH 220 Fall 2019"}`, nil, &resp); status != http.StatusOK {
+		t.Fatalf("status %d, want 200", status)
+	}
+	if resp.Parsed != 2 || resp.Failed != 0 || resp.Results[0].Semester != "Fall" || resp.Results[0].Year != 2019 {
+		t.Errorf("response %+v", resp)
+	}
+}
+
+func TestBadRequests(t *testing.T) {
+	srv := testServer(t)
+
+	tests := []struct {
+		path   string
+		body   string
+		status int
+		err    string
+	}{
+		{"/parse", `{"input": `, http.StatusBadRequest, "invalid request body: unexpected EOF"},
+		{"/parse", `{"text": "CS 111 Fall 2016"}`, http.StatusBadRequest, `invalid request body: json: unknown field "text"`},
+		{"/parse", `{}`, http.StatusBadRequest, `missing "input"`},
+		{"/parse", `{"input": "CS 111 Otoño 2019", "locale": "xx"}`, http.StatusBadRequest, `courseparser: no semester names for locale "xx" (expecting one of es, de, fr or auto)`},
+		{"/parse/batch", `{"inputs": "CS 111 Fall 2016"}`, http.StatusBadRequest, "invalid request body: json: cannot unmarshal string into Go struct field batchRequest.inputs of type []string"},
+	}
+	for _, test := range tests {
+		var resp errorResponse
+		if status := post(t, srv, test.path, test.body, nil, &resp); status != test.status || resp.Error != test.err {
+			t.Errorf("POST %s %s: %d %q, want %d %q", test.path, test.body, status, resp.Error, test.status, test.err)
+		}
+	}
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	srv := testServer(t)
+
+	for _, path := range []string{"/parse", "/parse/batch", "/parse/list"} {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusMethodNotAllowed || resp.Header.Get("Allow") != http.MethodPost {
+			t.Errorf("GET %s: %d Allow %q, want 405 Allow POST", path, resp.StatusCode, resp.Header.Get("Allow"))
+		}
+	}
+}
+
+func TestLocale(t *testing.T) {
+	srv := testServer(t)
+
+	var r result
+	if status := post(t, srv, "/parse", `{"input": "CS 111 Otoño 2019", "locale": "es"}`, nil, &r); status != http.StatusOK {
+		t.Fatalf("status %d, want 200", status)
+	}
+	if r.Semester != "Fall" || r.Locale != "es" {
+		t.Errorf("semester %q locale %q, want Fall es", r.Semester, r.Locale)
+	}
+
+	// The request locale applies to that request only
+	if status := post(t, srv, "/parse", `{"input": "CS 111 Otoño 2019"}`, nil, &r); status != http.StatusUnprocessableEntity {
+		t.Errorf("status %d without a locale, want 422", status)
+	}
+}
+
+func TestAcceptLanguage(t *testing.T) {
+	srv := testServer(t)
+
+	tests := []struct {
+		acceptLanguage string
+		message        string
+	}{
+		{"", "'Fxll' is not a semester. Did you mean Fall?"},
+		{"es", "'Fxll' no es un semestre. ¿Quiso decir Fall?"},
+		{"es-MX,es;q=0.9,en;q=0.8", "'Fxll' no es un semestre. ¿Quiso decir Fall?"},
+		{"fr-CA, fr;q=0.9", "'Fxll' is not a semester. Did you mean Fall?"},
+		{"fr, es;q=0.5", "'Fxll' no es un semestre. ¿Quiso decir Fall?"},
+	}
+	for _, test := range tests {
+		var r result
+		post(t, srv, "/parse", `{"input": "CS 111 Fxll 2016"}`, map[string]string{"Accept-Language": test.acceptLanguage}, &r)
+		if r.Error == nil || r.Error.UserMessage != test.message {
+			t.Errorf("Accept-Language %q: %+v, want %q", test.acceptLanguage, r.Error, test.message)
+		}
+	}
+
+	// -lang sets the language of a request without Accept-Language
+	srv = testServer(t, "-lang", "es")
+	var resp batchResponse
+	post(t, srv, "/parse/batch", `{"inputs": ["CS 111 Fxll 2016"]}`, nil, &resp)
+	if msg := resp.Results[0].Error.UserMessage; msg != "'Fxll' no es un semestre. ¿Quiso decir Fall?" {
+		t.Errorf("-lang es: %q", msg)
+	}
+}
@@ -0,0 +1,177 @@
+package courseparser
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+)
+
+//===========================================================
+//============ Ambiguous Input Resolution ===================
+//===========================================================
+// In "CS 2018 Fall" the number after [Dept] is either the [Course] (and the
+// [Year] is missing) or the [Year] (and the [Course] is missing). Whenever
+// the [Course] token is also a valid year, Parse() scores both readings:
+//
+//   reading                  complete   one token missing   anything else
+//   as typed (course 2018)   1.0        0.5                 rejected
+//   number as [Year]         -          0.5                 rejected
+//
+// and then weighs them with what is known about the number
+//   - with a Catalog, an unknown course rejects the course reading, a known
+//     one makes it more likely than the year reading (x 0.7, the missing
+//     course can not be checked)
+//   - without a Catalog, a course number that looks like a year is less
+//     likely than a year (x 0.9)
+//
+// The best reading is returned with its Confidence (its share of the total
+// score), unless the runner up is within Parser.AmbiguityMargin of it, when
+// Parse() fails with ErrAmbiguous listing the Candidates.
+
+// Default Parser.AmbiguityMargin
+const DefaultAmbiguityMargin = 0.1
+
+// Interpretation is one reading of an ambiguous input
+type Interpretation struct {
+	Reading   string // e.g. "2018 is the course number"
+	Selection CourseSelection
+	Err       error   // why the reading is incomplete, nil if complete
+	Score     float64 // likelihood of the reading, see above
+}
+
+// Scores of the readings
+const (
+	scoreComplete     = 1.0
+	scoreMissingToken = 0.5
+	factorYearLike    = 0.9 // course number that looks like a year, no Catalog
+	factorUnchecked   = 0.7 // reading whose course the Catalog can not check
+)
+
+// Scores the readings of a [Course] token that is also a valid year and
+// picks the best one
+//
+//funcid:1300
+func (p *Parser) resolveAmbiguity(input string, sel CourseSelection, err error) (CourseSelection, error) {
+	var candidates []Interpretation
+	var total float64
+
+	p.trace(slog.LevelInfo, "1300.10", "IN- : resolveAmbiguity()", nil, "tokens", sel.Tokens())
+
+	// The input as typed
+	asTyped := Interpretation{Reading: sel.Course + " is the course number", Selection: sel, Err: err, Score: readingScore(err)}
+	if p.Catalog == nil {
+		asTyped.Score *= factorYearLike
+	}
+
+	// The number as the [Year]
+	yearSel, yearErr := p.parseMissingCourse(input)
+	asYear := Interpretation{Reading: sel.Course + " is the year, the course number is missing", Selection: yearSel, Err: yearErr}
+	if errors.Is(yearErr, ErrMissingCourse) {
+		asYear.Score = scoreMissingToken
+	}
+	if p.Catalog != nil {
+		asYear.Score *= factorUnchecked
+	}
+
+	for _, c := range []Interpretation{asTyped, asYear} {
+		if c.Score > 0 {
+			candidates = append(candidates, c)
+			total += c.Score
+		}
+	}
+
+	// Neither reading makes sense, keep the error as typed
+	if len(candidates) == 0 {
+		return sel, err
+	}
+
+	if len(candidates) > 1 && candidates[1].Score > candidates[0].Score {
+		candidates[0], candidates[1] = candidates[1], candidates[0]
+	}
+	best := candidates[0]
+	best.Selection.Confidence = best.Score / total
+
+	p.trace(slog.LevelInfo, "1300.50", "MID : resolveAmbiguity()", nil, "readings", len(candidates), "best", best.Reading, "confidence", best.Selection.Confidence)
+
+	if len(candidates) > 1 && best.Score-candidates[1].Score < p.AmbiguityMargin {
+		var readings []string
+		for _, c := range candidates {
+			readings = append(readings, c.Reading)
+		}
+		pe := &ParseError{Code: "1300.60", Offset: -1, Msg: "Ambiguous input, either " + strings.Join(readings, " or "), Kind: ErrAmbiguous, Candidates: candidates}
+		pe.with("course", sel.Course)
+		if at := Locate(yearErr); at != nil {
+			pe.Offset, pe.Column, pe.Char = at.Offset, at.Column, at.Char
+		}
+		sel.Confidence = asTyped.Score / total
+		return sel, pe
+	}
+
+	return best.Selection, best.Err
+}
+
+// Scores a reading by how complete it is
+func readingScore(err error) float64 {
+	switch {
+	case err == nil:
+		return scoreComplete
+	case isMissingToken(err):
+		return scoreMissingToken
+	}
+	return 0
+}
+
+// Reports whether err is only a single missing [Course], [Semester] or [Year] token
+func isMissingToken(err error) bool {
+	pe := Locate(err)
+	if pe == nil {
+		return false
+	}
+	for _, kind := range []error{ErrMissingCourse, ErrMissingSemester, ErrMissingYear} {
+		if pe.Is(kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reports whether the [Course] token could also be read as the [Year]
+func (p *Parser) courseLooksLikeYear(sel CourseSelection) bool {
+	if sel.Course == "" || sel.Suffix != "" || sel.Section != "" {
+		return false
+	}
+	_, err := p.validateYear(sel.Course)
+	return err == nil
+}
+
+// Parses "[Dept] [OfferSession]", reading the number after [Dept] as the
+// [Year]. When the rest parses, the result reports the course number as
+// missing (ErrMissingCourse), any other error rejects the reading.
+//
+//funcid:1350
+func (p *Parser) parseMissingCourse(input string) (CourseSelection, error) {
+	var sel CourseSelection
+
+	inStr := newChStr(input)
+	if err := p.skipSpacesDelims(&inStr); err != nil {
+		return sel, err
+	}
+	if err := p.getDeptToken(&inStr, &sel); err != nil {
+		return sel, err
+	}
+	if inStr.indx < inStr.len && (p.isDelimiter(inStr.data[inStr.indx]) || p.isFieldSeparator(inStr.data[inStr.indx])) {
+		inStr.indx++
+	}
+	if inStr.indx >= inStr.len {
+		return sel, inStr.errorHere("1350.20", "Missing Year Data ", ErrMissingYear, nil)
+	}
+
+	start := inStr.indx
+	if err := p.getOfferSession(&inStr, &sel); err != nil {
+		return sel, err
+	}
+	if err := p.expectEnd(&inStr, "1350.30"); err != nil {
+		return sel, err
+	}
+	return sel, inStr.errorAt("1350.40", start, "Missing Course number before [OfferSession]", ErrMissingCourse, nil)
+}
@@ -0,0 +1,73 @@
+package courseparser
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestAmbiguity(t *testing.T) {
+	catalog := NewCatalog()
+	catalog.AddCourse("CS", "2018")
+
+	tests := []struct {
+		input      string
+		catalog    *Catalog
+		margin     float64
+		kind       error // nil when the input parses
+		code       string
+		confidence float64
+		candidates int
+	}{
+		// course 2018 missing its year (0.45) against year 2018 missing the course (0.5)
+		{"CS 2018 Fall", nil, DefaultAmbiguityMargin, ErrAmbiguous, "1300.60", 0.45 / 0.95, 2},
+		{"CS 2018 Fall", nil, 0, ErrMissingCourse, "1350.40", 0.5 / 0.95, 0},
+		// a known course (0.5) against the unchecked year reading (0.35)
+		{"CS 2018 Fall", catalog, DefaultAmbiguityMargin, ErrMissingYear, "800.38", 0.5 / 0.85, 0},
+		// an unknown course leaves the year reading only
+		{"CS 2015 Spring", catalog, DefaultAmbiguityMargin, ErrMissingCourse, "1350.40", 1, 0},
+		// complete, or not a year, or no reading completes: no ambiguity
+		{"CS 2018 Fall 2019", nil, DefaultAmbiguityMargin, nil, "", 1, 0},
+		{"CS 1999 Fall", nil, DefaultAmbiguityMargin, ErrMissingYear, "800.38", 1, 0},
+		{"CS 2018 Fxll", nil, DefaultAmbiguityMargin, ErrInvalidSemester, "950.35", 1, 0},
+	}
+
+	for _, test := range tests {
+		p := testParser()
+		p.Catalog, p.AmbiguityMargin = test.catalog, test.margin
+		sel, err := p.Parse(test.input)
+		if math.Abs(sel.Confidence-test.confidence) > 1e-9 {
+			t.Errorf("%q margin %v: confidence %v, want %v", test.input, test.margin, sel.Confidence, test.confidence)
+		}
+		if test.kind == nil {
+			if err != nil {
+				t.Errorf("%q: %v", test.input, err)
+			}
+			continue
+		}
+		pe := Locate(err)
+		if !errors.Is(err, test.kind) || pe == nil || pe.Code != test.code || len(pe.Candidates) != test.candidates {
+			t.Errorf("%q margin %v: error %v at %+v, want %v code %s with %d candidates", test.input, test.margin, err, pe, test.kind, test.code, test.candidates)
+		}
+	}
+}
+
+// The candidates of an ambiguous input are best first, and the error points at the number
+func TestAmbiguousCandidates(t *testing.T) {
+	_, err := testParser().Parse("CS 2018 Fall")
+
+	pe := Locate(err)
+	if pe == nil || len(pe.Candidates) != 2 {
+		t.Fatalf("error %v, want 2 candidates", err)
+	}
+	if pe.Offset != 3 {
+		t.Errorf("offset %d, want 3", pe.Offset)
+	}
+	year, course := pe.Candidates[0], pe.Candidates[1]
+	if year.Selection.Year != 2018 || year.Selection.Course != "" || !errors.Is(year.Err, ErrMissingCourse) {
+		t.Errorf("best candidate %+v, want year 2018 without course", year)
+	}
+	if course.Selection.Course != "2018" || course.Selection.Year != 0 || !errors.Is(course.Err, ErrMissingYear) || course.Score >= year.Score {
+		t.Errorf("runner up %+v, want course 2018 without year", course)
+	}
+}
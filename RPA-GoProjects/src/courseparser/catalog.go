@@ -0,0 +1,303 @@
+package courseparser
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+//===========================================================
+//============ Course Catalog ===============================
+//===========================================================
+
+// Catalog is the optional list of valid departments, their course numbers
+// and the terms each course is offered in. When a Parser has a Catalog,
+// unknown departments, unknown courses and courses not offered in the
+// parsed [OfferSession] are rejected.
+type Catalog struct {
+	depts map[string]*catalogDept // upper case dept code => dept
+	order []string                // dept codes in the order they were added
+}
+
+type catalogDept struct {
+	code    string
+	name    string
+	courses map[string][]Offering // course number => terms offered
+}
+
+// Offering is one term a course is offered in. A zero Year means the
+// Semester of every year.
+type Offering struct {
+	Semester string
+	Year     int
+}
+
+// String formats the offering as "Fall" or "Fall 2019"
+func (o Offering) String() string {
+	if o.Year == 0 {
+		return o.Semester
+	}
+	return o.Semester + " " + strconv.Itoa(o.Year)
+}
+
+// ParseOffering parses "Fall" or "Fall 2019". The semester is a term or an
+// alias of semesters (the DefaultSemesters when nil), and is returned as
+// its term, e.g. "fa 2019" is Fall 2019.
+func ParseOffering(s string, semesters *SemesterDict) (Offering, error) {
+	var o Offering
+
+	if semesters == nil {
+		semesters = DefaultSemesters()
+	}
+
+	fields := strings.Fields(s)
+	if len(fields) > 1 {
+		if year, err := strconv.Atoi(fields[len(fields)-1]); err == nil {
+			o.Year = year
+			fields = fields[:len(fields)-1]
+		}
+	}
+	if len(fields) == 0 {
+		return Offering{}, fmt.Errorf("courseparser: invalid offering %q, expecting \"Semester\" or \"Semester Year\"", s)
+	}
+
+	name := strings.Join(fields, " ")
+	if term, inMap := semesters.Lookup(name); inMap {
+		o.Semester = term
+		return o, nil
+	}
+	for _, term := range semesters.Terms() {
+		if strings.EqualFold(term, name) {
+			o.Semester = term
+			return o, nil
+		}
+	}
+	return Offering{}, fmt.Errorf("courseparser: invalid offering %q, %q is not a semester (expecting one of %s)", s, name, strings.Join(semesters.Terms(), ", "))
+}
+
+// NewCatalog returns an empty catalog
+func NewCatalog() *Catalog {
+	return &Catalog{depts: make(map[string]*catalogDept)}
+}
+
+// AddDept registers a department code and its (optional) name
+func (c *Catalog) AddDept(code, name string) {
+	key := strings.ToUpper(code)
+
+	if d, inMap := c.depts[key]; inMap {
+		if name != "" {
+			d.name = name
+		}
+		return
+	}
+	c.depts[key] = &catalogDept{code: code, name: name, courses: make(map[string][]Offering)}
+	c.order = append(c.order, code)
+}
+
+// AddCourse registers a course of dept, adding the department if needed.
+// A course listed without offerings is offered in every term.
+func (c *Catalog) AddCourse(dept, course string, offered ...Offering) {
+	c.AddDept(dept, "")
+	d := c.depts[strings.ToUpper(dept)]
+	d.courses[course] = append(d.courses[course], offered...)
+}
+
+// Dept returns the catalog spelling of a department code, ignoring case
+func (c *Catalog) Dept(dept string) (string, bool) {
+	d, inMap := c.depts[strings.ToUpper(dept)]
+	if !inMap {
+		return "", false
+	}
+	return d.code, true
+}
+
+// DeptName returns the name of a department, if one was given
+func (c *Catalog) DeptName(dept string) string {
+	if d, inMap := c.depts[strings.ToUpper(dept)]; inMap {
+		return d.name
+	}
+	return ""
+}
+
+// Depts returns every department code in the order they were added
+func (c *Catalog) Depts() []string {
+	return append([]string(nil), c.order...)
+}
+
+// HasCourse reports whether dept offers the course number
+func (c *Catalog) HasCourse(dept, course string) bool {
+	d, inMap := c.depts[strings.ToUpper(dept)]
+	if !inMap {
+		return false
+	}
+	_, inMap = d.courses[course]
+	return inMap
+}
+
+// Offerings returns the terms a course is offered in, empty for every term
+func (c *Catalog) Offerings(dept, course string) []Offering {
+	d, inMap := c.depts[strings.ToUpper(dept)]
+	if !inMap {
+		return nil
+	}
+	return append([]Offering(nil), d.courses[course]...)
+}
+
+// Offered reports whether a known course is offered in semester of year
+func (c *Catalog) Offered(dept, course, semester string, year int) bool {
+	offerings := c.Offerings(dept, course)
+	if len(offerings) == 0 {
+		return c.HasCourse(dept, course)
+	}
+	for _, o := range offerings {
+		if strings.EqualFold(o.Semester, semester) && (o.Year == 0 || o.Year == year) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the catalog entry of a selection's course: the course number with
+// its suffix (e.g. "111L") when the catalog lists it, else the plain number,
+// or "" when neither is listed
+func (p *Parser) catalogCourse(sel *CourseSelection) string {
+	if sel.Suffix != "" && p.Catalog.HasCourse(sel.Dept, sel.Course+sel.Suffix) {
+		return sel.Course + sel.Suffix
+	}
+	if p.Catalog.HasCourse(sel.Dept, sel.Course) {
+		return sel.Course
+	}
+	return ""
+}
+
+//===========================================================
+//============ Course Catalog Files =========================
+//===========================================================
+//   CSV  : dept,course,offered,name     e.g.  CS,111,Fall;Spring 2020,Computer Science
+//          (header row optional, offered terms separated by ';', empty course only declares the dept)
+//   JSON : {"departments": [{"code": "CS", "name": "Computer Science",
+//            "courses": [{"number": "111", "offered": ["Fall", "Spring 2020"]}]}]}
+
+// LoadCatalog reads a catalog from a .csv or .json file, the offered terms
+// being semesters of the dictionary (the DefaultSemesters when nil)
+func LoadCatalog(path string, semesters *SemesterDict) (*Catalog, error) {
+	var c *Catalog
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv":
+		c, err = ReadCatalogCSV(f, semesters)
+	case ".json":
+		c, err = ReadCatalogJSON(f, semesters)
+	default:
+		err = fmt.Errorf("courseparser: unknown catalog format %q", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// ReadCatalogCSV reads "dept,course,offered,name" records
+func ReadCatalogCSV(r io.Reader, semesters *SemesterDict) (*Catalog, error) {
+	c := NewCatalog()
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	cr.Comment = '#'
+
+	for first := true; ; first = false {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		for len(record) < 4 {
+			record = append(record, "")
+		}
+		dept, course, offered, name := strings.TrimSpace(record[0]), strings.TrimSpace(record[1]), record[2], strings.TrimSpace(record[3])
+		if first && strings.EqualFold(dept, "dept") {
+			continue
+		}
+		if dept == "" {
+			line, _ := cr.FieldPos(0)
+			return nil, fmt.Errorf("courseparser: line %d: missing dept", line)
+		}
+
+		c.AddDept(dept, name)
+		if course == "" {
+			continue
+		}
+
+		var offerings []Offering
+		for _, term := range strings.Split(offered, ";") {
+			if strings.TrimSpace(term) == "" {
+				continue
+			}
+			o, err := ParseOffering(term, semesters)
+			if err != nil {
+				line, _ := cr.FieldPos(2)
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			offerings = append(offerings, o)
+		}
+		c.AddCourse(dept, course, offerings...)
+	}
+	return c, nil
+}
+
+type catalogJSON struct {
+	Departments []struct {
+		Code    string `json:"code"`
+		Name    string `json:"name"`
+		Courses []struct {
+			Number  string   `json:"number"`
+			Offered []string `json:"offered"`
+		} `json:"courses"`
+	} `json:"departments"`
+}
+
+// ReadCatalogJSON reads a {"departments": [...]} catalog
+func ReadCatalogJSON(r io.Reader, semesters *SemesterDict) (*Catalog, error) {
+	var doc catalogJSON
+
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	c := NewCatalog()
+	for _, d := range doc.Departments {
+		if d.Code == "" {
+			return nil, fmt.Errorf("courseparser: department without code")
+		}
+		c.AddDept(d.Code, d.Name)
+		for _, course := range d.Courses {
+			var offerings []Offering
+
+			if strings.TrimSpace(course.Number) == "" {
+				return nil, fmt.Errorf("courseparser: department %s: course without number", d.Code)
+			}
+			for _, term := range course.Offered {
+				o, err := ParseOffering(term, semesters)
+				if err != nil {
+					return nil, err
+				}
+				offerings = append(offerings, o)
+			}
+			c.AddCourse(d.Code, course.Number, offerings...)
+		}
+	}
+	return c, nil
+}
@@ -0,0 +1,151 @@
+package courseparser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testCatalogCSV = `dept,course,offered,name
+CS,111,Fall;Spring 2020,Computer Science
+CS,111L,,
+CS,220,fa;SP 2021
+MATH,,,Mathematics
+MATH,220,Summer
+`
+
+func TestParseOffering(t *testing.T) {
+	summers := NewSemesterDict()
+	summers.Add("Summer I", "SUI")
+	summers.Add("Fall", "F")
+
+	tests := []struct {
+		input     string
+		semesters *SemesterDict
+		want      Offering
+		err       string
+	}{
+		{"Fall", nil, Offering{"Fall", 0}, ""},
+		{" fall  2019 ", nil, Offering{"Fall", 2019}, ""},
+		{"SP 2020", nil, Offering{"Spring", 2020}, ""},
+		{"Summer I 2020", summers, Offering{"Summer I", 2020}, ""},
+		{"sui", summers, Offering{"Summer I", 0}, ""},
+		{"", nil, Offering{}, "expecting \"Semester\" or \"Semester Year\""},
+		{"2019", nil, Offering{}, `"2019" is not a semester`},
+		{"Fxll 2019", nil, Offering{}, `"Fxll" is not a semester`},
+		{"Fall 2019 x", nil, Offering{}, `"Fall 2019 x" is not a semester`},
+		{"Spring 2020", summers, Offering{}, "expecting one of Summer I, Fall"},
+	}
+	for _, test := range tests {
+		got, err := ParseOffering(test.input, test.semesters)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%q: error %v, want %q", test.input, err, test.err)
+			}
+			continue
+		}
+		if err != nil || got != test.want {
+			t.Errorf("%q: %+v %v, want %+v", test.input, got, err, test.want)
+		}
+	}
+}
+
+func TestReadCatalogCSV(t *testing.T) {
+	c, err := ReadCatalogCSV(strings.NewReader(testCatalogCSV), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if depts := strings.Join(c.Depts(), ","); depts != "CS,MATH" {
+		t.Errorf("Depts = %s", depts)
+	}
+	if dept, ok := c.Dept("cs"); !ok || dept != "CS" || c.DeptName("CS") != "Computer Science" {
+		t.Errorf("Dept(cs) = %q %v, name %q", dept, ok, c.DeptName("CS"))
+	}
+	for _, test := range []struct {
+		dept, course, semester string
+		year                   int
+		want                   bool
+	}{
+		{"CS", "111", "Fall", 2016, true},
+		{"CS", "111", "Spring", 2020, true},
+		{"CS", "111", "Spring", 2021, false},
+		{"CS", "111L", "Winter", 2021, true},
+		{"CS", "220", "Fall", 2019, true},
+		{"cs", "220", "Spring", 2021, true},
+		{"MATH", "220", "Fall", 2019, false},
+		{"MATH", "111", "Fall", 2019, false},
+		{"BIO", "111", "Fall", 2019, false},
+	} {
+		if got := c.Offered(test.dept, test.course, test.semester, test.year); got != test.want {
+			t.Errorf("Offered(%s %s %s %d) = %v, want %v", test.dept, test.course, test.semester, test.year, got, test.want)
+		}
+	}
+
+	for _, input := range []string{
+		",111,Fall",
+		"CS,111,Fxll 2019",
+		"CS,111,Fall;2019",
+	} {
+		if _, err := ReadCatalogCSV(strings.NewReader(input), nil); err == nil {
+			t.Errorf("%q: no error", input)
+		}
+	}
+}
+
+func TestReadCatalogJSON(t *testing.T) {
+	c, err := ReadCatalogJSON(strings.NewReader(`{"departments": [{"code": "CS", "name": "Computer Science",
+		"courses": [{"number": "111", "offered": ["Fall", "sp 2020"]}, {"number": "220"}]}]}`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.Offered("CS", "111", "Spring", 2020) || c.Offered("CS", "111", "Spring", 2021) || !c.Offered("CS", "220", "Winter", 2021) {
+		t.Errorf("Offerings(CS 111) = %v", c.Offerings("CS", "111"))
+	}
+
+	for _, input := range []string{
+		`{"departments": [{"name": "Computer Science"}]}`,
+		`{"departments": [{"code": "CS", "courses": [{"number": ""}]}]}`,
+		`{"departments": [{"code": "CS", "courses": [{"number": " ", "offered": ["Fall"]}]}]}`,
+		`{"departments": [{"code": "CS", "courses": [{"number": "111", "offered": ["Autumn"]}]}]}`,
+		`{"departments": [`,
+	} {
+		if _, err := ReadCatalogJSON(strings.NewReader(input), nil); err == nil {
+			t.Errorf("%s: no error", input)
+		}
+	}
+}
+
+func TestParseCatalog(t *testing.T) {
+	c, err := ReadCatalogCSV(strings.NewReader(testCatalogCSV), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := testParser()
+	p.Catalog = c
+
+	tests := []struct {
+		input string
+		kind  error
+	}{
+		{"cs 111 Fall 2016", nil},
+		{"CS 111L Winter 2016", nil},
+		{"MATH 220 Summer 2019", nil},
+		{"BIO 111 Fall 2016", ErrUnknownDept},
+		{"CS 999 Fall 2016", ErrUnknownCourse},
+		{"CS 111 Spring 2021", ErrCourseNotOffered},
+		{"MATH 220 Fall 2019", ErrCourseNotOffered},
+	}
+	for _, test := range tests {
+		sel, err := p.Parse(test.input)
+		if test.kind == nil {
+			if err != nil || sel.Dept != strings.ToUpper(sel.Dept) {
+				t.Errorf("%q: %v %v", test.input, sel.Tokens(), err)
+			}
+			continue
+		}
+		if !errors.Is(err, test.kind) {
+			t.Errorf("%q: error %v, want %v", test.input, err, test.kind)
+		}
+	}
+}
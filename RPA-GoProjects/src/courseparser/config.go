@@ -0,0 +1,346 @@
+package courseparser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+//===========================================================
+//============ Config Files =================================
+//===========================================================
+// The semester dictionary and the message catalogs are flat "key => value"
+// tables, read from JSON, YAML or TOML by the decoders of those formats. The
+// keys keep their order in the file, and the table may be wrapped in one
+// table named after its content, e.g. "[semesters]" in TOML. A value is a
+// string or a list of strings, anything else (a number, a nested table, ...)
+// is an error naming the key and, but for TOML, its line.
+
+// One "key => value" entry of a config file table
+type configEntry struct {
+	Key   string
+	Line  int // line of the key in the file, 0 when the format does not tell
+	Value any // string, []string, []configEntry (a table), nil, or the name of an unsupported type
+}
+
+// Reads the table of a config file in format "json", "yaml" or "toml",
+// unwrapping a single table named section
+func readConfig(r io.Reader, format string, section string) ([]configEntry, error) {
+	var entries []configEntry
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(format) {
+	case "json":
+		entries, err = readConfigJSON(data)
+	case "yaml", "yml":
+		entries, err = readConfigYAML(data)
+	case "toml":
+		entries, err = readConfigTOML(data)
+	default:
+		return nil, fmt.Errorf("courseparser: unknown %s format %q", section, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 1 && strings.EqualFold(entries[0].Key, section) {
+		if table, ok := entries[0].Value.([]configEntry); ok {
+			return table, nil
+		}
+	}
+	return entries, nil
+}
+
+// The value of a "key => aliases" entry, a string standing for a list of one
+func (e *configEntry) list() ([]string, error) {
+	switch v := e.Value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{v}, nil
+	case []string:
+		return v, nil
+	}
+	return nil, e.errorf("expecting a string or a list of strings, not %s", e.typeName())
+}
+
+// The value of a "key => text" entry
+func (e *configEntry) text() (string, error) {
+	if v, ok := e.Value.(string); ok {
+		return v, nil
+	}
+	return "", e.errorf("expecting a string, not %s", e.typeName())
+}
+
+func (e *configEntry) typeName() string {
+	switch v := e.Value.(type) {
+	case nil:
+		return "null"
+	case []string:
+		return "a list"
+	case []configEntry:
+		return "a table"
+	case unsupported:
+		return string(v)
+	}
+	return "a string"
+}
+
+// Name of a value type the tables do not take, e.g. "a number"
+type unsupported string
+
+// Error about the entry, positioned at its line when known
+func (e *configEntry) errorf(format string, args ...any) error {
+	if e.Line > 0 {
+		return fmt.Errorf("courseparser: line %d: %q: %s", e.Line, e.Key, fmt.Sprintf(format, args...))
+	}
+	return fmt.Errorf("courseparser: %q: %s", e.Key, fmt.Sprintf(format, args...))
+}
+
+// Decodes token by token so the order of the object keys is kept
+func readConfigJSON(data []byte) ([]configEntry, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	line := func() int {
+		return 1 + bytes.Count(data[:dec.InputOffset()], []byte("\n"))
+	}
+
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, fmt.Errorf("courseparser: expecting a JSON object")
+	}
+	entries, err := readJSONObject(dec, line)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("courseparser: line %d: unexpected text after the JSON object", line())
+	}
+	return entries, nil
+}
+
+// Reads the members of an object up to its closing '}'
+func readJSONObject(dec *json.Decoder, line func() int) ([]configEntry, error) {
+	var entries []configEntry
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("courseparser: line %d: %w", line(), err)
+		}
+		e := configEntry{Key: tok.(string), Line: line()}
+
+		if tok, err = dec.Token(); err != nil {
+			return nil, fmt.Errorf("courseparser: line %d: %w", line(), err)
+		}
+		switch v := tok.(type) {
+		case string, nil:
+			e.Value = v
+		case json.Delim:
+			if v == '{' {
+				if e.Value, err = readJSONObject(dec, line); err != nil {
+					return nil, err
+				}
+				break
+			}
+			items := []string{}
+			for dec.More() {
+				tok, err := dec.Token()
+				if err != nil {
+					return nil, fmt.Errorf("courseparser: line %d: %w", line(), err)
+				}
+				item, ok := tok.(string)
+				if !ok {
+					return nil, e.errorf("expecting a list of strings")
+				}
+				items = append(items, item)
+			}
+			if _, err := dec.Token(); err != nil { // ']'
+				return nil, fmt.Errorf("courseparser: line %d: %w", line(), err)
+			}
+			e.Value = items
+		case bool:
+			e.Value = unsupported("a boolean")
+		default:
+			e.Value = unsupported("a number")
+		}
+		entries = append(entries, e)
+	}
+	if _, err := dec.Token(); err != nil { // '}'
+		return nil, fmt.Errorf("courseparser: line %d: %w", line(), err)
+	}
+	return entries, nil
+}
+
+func readConfigYAML(data []byte) ([]configEntry, error) {
+	var doc yaml.Node
+
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("courseparser: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("courseparser: line %d: expecting a YAML mapping", root.Line)
+	}
+	return readYAMLMapping(root)
+}
+
+func readYAMLMapping(node *yaml.Node) ([]configEntry, error) {
+	var entries []configEntry
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], resolveYAML(node.Content[i+1])
+		e := configEntry{Key: key.Value, Line: key.Line}
+
+		switch value.Kind {
+		case yaml.ScalarNode:
+			switch value.Tag {
+			case "!!null":
+			case "!!str":
+				e.Value = value.Value
+			default:
+				e.Value = unsupported(yamlType(value))
+			}
+		case yaml.SequenceNode:
+			items := []string{}
+			for _, item := range value.Content {
+				if item = resolveYAML(item); item.Tag != "!!str" {
+					e.Value = unsupported("a list holding " + yamlType(item))
+					break
+				}
+				items = append(items, item.Value)
+			}
+			if e.Value == nil {
+				e.Value = items
+			}
+		case yaml.MappingNode:
+			table, err := readYAMLMapping(value)
+			if err != nil {
+				return nil, err
+			}
+			e.Value = table
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// Name of the type of a YAML node, by its resolved tag
+func yamlType(node *yaml.Node) string {
+	switch node.Tag {
+	case "!!str":
+		return "a string"
+	case "!!null":
+		return "null"
+	case "!!bool":
+		return "a boolean"
+	case "!!int", "!!float":
+		return "a number"
+	case "!!seq":
+		return "a list"
+	case "!!map":
+		return "a table"
+	}
+	return "a " + strings.TrimPrefix(node.Tag, "!!")
+}
+
+func resolveYAML(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	return node
+}
+
+// TOML tells the keys in file order, but not their lines
+func readConfigTOML(data []byte) ([]configEntry, error) {
+	var doc map[string]any
+
+	md, err := toml.Decode(string(data), &doc)
+	if err != nil {
+		return nil, fmt.Errorf("courseparser: %w", err)
+	}
+	return readTOMLTable(doc, md.Keys(), 0), nil
+}
+
+// Entries of the table at depth of the key paths, in the order of keys. A key
+// the order does not tell (e.g. the parent of a dotted key) comes last.
+func readTOMLTable(table map[string]any, keys []toml.Key, depth int) []configEntry {
+	var entries []configEntry
+	var order []string
+
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		if len(key) == depth+1 && !seen[key[depth]] {
+			seen[key[depth]] = true
+			order = append(order, key[depth])
+		}
+	}
+	var rest []string
+	for name := range table {
+		if !seen[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+
+	for _, name := range append(order, rest...) {
+		e := configEntry{Key: name}
+
+		switch v := table[name].(type) {
+		case string:
+			e.Value = v
+		case []any:
+			items := []string{}
+			for _, item := range v {
+				s, ok := item.(string)
+				if !ok {
+					e.Value = unsupported("a list holding " + tomlType(item))
+					break
+				}
+				items = append(items, s)
+			}
+			if e.Value == nil {
+				e.Value = items
+			}
+		case map[string]any:
+			var sub []toml.Key
+			for _, k := range keys {
+				if len(k) > depth+1 && k[depth] == name {
+					sub = append(sub, k)
+				}
+			}
+			e.Value = readTOMLTable(v, sub, depth+1)
+		default:
+			e.Value = unsupported(tomlType(v))
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func tomlType(v any) string {
+	switch v.(type) {
+	case string:
+		return "a string"
+	case bool:
+		return "a boolean"
+	case int64, float64:
+		return "a number"
+	case []any:
+		return "a list"
+	case map[string]any, []map[string]any:
+		return "a table"
+	}
+	return "a date"
+}
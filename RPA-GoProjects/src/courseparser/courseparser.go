@@ -0,0 +1,845 @@
+//  ================================================================================================================
+//  PROBLEM    : Write a Parser to tokenize/validate Dept, Course, Semester, Year for "Course Selection input text"
+//  REQUIRMENT : INPUT "CS111 2016 Fall", "CS-111 Fall 2016", "CS 111 F2016"    OUTPUT: | CS | 111 | Fall | 2016 |
+//  AUTHOR     : Prasanna Amerasinghe
+//  DATE       : 09/01/2019
+//  ================================================================================================================
+//  Assumptions
+//  (NOTE: [DeptCourse] & [OfferSession] are considerd "Fields". [Dept], [Course], [Year], [Semester] are "Tokens"
+//  ----------------------------------------------------------------------------------------------------------------
+// 0) Input is Unicode text, NFKC normalized before parsing (full-width "２０１９" is 2019). Letters may be any
+//    script, Unicode whitespace counts as a space, and error positions are rune offsets into the input as typed
+// 1) Skip leading spaces and Delimiters before the [DeptCourse] Field  (Default delimiters are ' ' ,  ':'  ,  '-'  ,
+//    configurable with Parser.Delimiters)
+// 2) There should be ONE Field Seperator, by default "a space", between the [DeptCourse] AND [OfferSession] Fields
+//    (Parser.LenientSeparators allows any number of delimiters and Field Seperators instead)
+// 3) There should be either NOTHING or ONE delimiter between [Dept] and [Course] tokens
+// 3a) The [Course] token is a number, optionally followed by a letter suffix and/or a '-' section number
+//     e.g. "111", "111L" (lab), "101H" (honors), "111-02" (section 02), "111L-02"
+// 4) The [OfferSession] Field is either [Year]+[Semester] OR [Semester]+[Year].   Both token orders are supported!
+// 4a) The [OfferSession] may also come first ("Fall 2019 CS111") or around the [DeptCourse] ("2019 CS-111 Fall").
+//    Each such arrangement is an input Layout, see DefaultLayouts()
+// 4b) The [OfferSession] may be a range of terms "Fall 2019 - Spring 2020", "F19-S21" or an academic year
+//    "AY 2019-20". The start term must precede the end term, and the range is expanded to its terms in the
+//    calendar order of the Semester Lookup Dictionary (see terms.go)
+// 4c) With Parser.TermCodes, an institutional term code (Banner "201990", PeopleSoft "2199") may stand for
+//    the [Semester]+[Year] tokens, and formats the parsed term for the SIS (see termcodes.go)
+// 4d) A [Course] token that is also a valid year ("CS 2018 Fall") is ambiguous. Both readings are scored and the
+//    best one is returned with its Confidence, or ErrAmbiguous when no reading stands out
+// 5) There could be any number of valid delimiters between [Year] and [Semester] tokens
+// 6) [Year] token data is "range validated" against the Parser's YearWindow (by default 15 years back - 2 ahead).
+// 7) [Semester] token data is "lookup validated" using a Dictionary
+// 7a) With Parser.Locale, the [Semester] may also be named in another language ("Otoño", "Herbst", "Été").
+//    The parsed Semester is always the canonical term of the Dictionary (see locales.go)
+// 8) Only delimiters may follow the [OfferSession] Field. Several selections on one line are split into
+//    entries by ParseList() and each entry is parsed on its own. A [DeptCourse] only entry shares the next
+//    [OfferSession], an [OfferSession] only entry shares the previous course(s)
+// 9) When the Parser has a Catalog, [Dept] and [Course] are "lookup validated" against it, and the course
+//    must be offered in the [OfferSession]
+//===================================================================================================================
+//  Code Outline
+//  ------------
+// L0 :                                             Parse()
+//                                                   |
+// L0.5 :                          matchLayout() / runRule() over Parser.Layouts  (grammar.go), then validateOffering()
+//                        |---------------------------^---------------------------|
+// L1 :            getDeptCourse()                                        getOfferSession()
+//            (DeptCourseRules)                                         (OfferSessionRules)
+//               |---------^-------|                             |----------------^------------------|
+// L2 :   getDeptToken()    getCourseToken()             getYearToken()                      getSemesterToken()
+//                                                        getAcademicYearToken()      finishRange() (term ranges)
+//               |        |--------^---------|        |--------^--------|                  |---------^---------|
+// L3 :          |        |  getSuffixToken() |        |            validateYear()          |          validateSemester()
+//               |        |  getSectionToken()|        |                                    |
+//               |        |                   |        |                                    |
+// L4 :   getAlphaToken()    getNumberToken()    getNumberToken()                     getAlphaToken()
+// -----------------------------------------------------------------------------------------------------
+// Utility L-1       :        skipSpacesDelims()   isValid()    isDelim()       newChStr()
+// Utility Primitive :                isNumber()     isLetter()
+//
+//===================================================================================================================
+// Notes : Focused on a MVP solution using basic golang constructs.
+//       : Detailed error handling to improve customer feedback on data entry errors
+//       : funcid based Documentation / Tracing Scheme to improve code maintainability. (To enable set Parser.Logger)
+//===================================================================================================================
+
+// Package courseparser tokenizes and validates "Course Selection input text"
+// such as "CS-111 Fall 2016" into its Dept, Course, Semester and Year tokens.
+package courseparser
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// CH String Type, a rune cursor over the normalized input (see newChStr)
+type ChStr struct {
+	data []rune // NFKC normalized input
+	indx int    // rune index into data
+	len  int
+	src  []rune // input as typed
+	orig []int  // rune index into data => rune offset into src, one extra entry for the end of input
+
+	diags []Diagnostic // failures recovered from so far (see Parser.Recover)
+}
+
+// CourseSelection is the parsed result of one "Course Selection input text"
+type CourseSelection struct {
+	Dept     string
+	Course   string // course number, without suffix or section
+	Suffix   string // optional upper case course suffix, e.g. "L" of 111L
+	Section  string // optional section number, e.g. "02" of 111-02
+	Semester string
+	Year     int
+	Range    *TermRange // set when the [OfferSession] is a term range, Semester and Year are then its start
+	TermCode string     // institutional code of the term (see Parser.TermCodes), e.g. "201990"
+	Locale   string     // locale the [Semester] was named in (see Parser.Locale), "" for the Semesters
+
+	Corrections []Correction // tokens auto-corrected by the Parser, if any
+	Layout      string       // name of the input Layout matched, e.g. "course-session"
+	Confidence  float64      // 1, or the likelihood of the chosen reading of an ambiguous input (see resolveAmbiguity)
+}
+
+// Tokens returns the selection in the legacy positional token order
+// [Dept, Course, Year, Semester]
+func (cs CourseSelection) Tokens() []string {
+	var year string
+
+	if cs.Year != 0 {
+		year = strconv.Itoa(cs.Year)
+	}
+	return []string{cs.Dept, cs.CourseCode(), year, cs.Semester}
+}
+
+// CourseCode returns the full course designation, e.g. "111", "111L" or "111L-02"
+func (cs CourseSelection) CourseCode() string {
+	code := cs.Course + cs.Suffix
+	if cs.Section != "" {
+		code += string(SectionSeparator) + cs.Section
+	}
+	return code
+}
+
+// [Course] token suffix and section limits
+const MaxSuffixLen = 2
+const MaxSectionLen = 3
+const SectionSeparator = '-'
+
+// Parser holds one parsing configuration. A Parser is only read while
+// parsing, all cursor state lives in the per call ChStr, so one Parser may be
+// shared by any number of goroutines as long as its fields are not changed
+// concurrently. The zero Parser is ready to use: the fields it leaves unset
+// take the defaults of New() (see withDefaults).
+type Parser struct {
+	// Field Seperator
+	// Assumption : "There is always "a space" after the Course Number and before Semester+Year
+	// (Comment : When input data entry is "form based", the "Field Seperator" could ideally be a non-keyboard
+	//            character, inserted by mobile/web client code. Can be used to increase parsing concurrency, accuracy.
+	//            better error handling etc. e.g. when parsing input string "CS 2018 Fall", Is 2018 a year or a course?
+	//            (see resolveAmbiguity)
+	// Any Unicode space matches a ' ' FieldSeparator
+	FieldSeparator rune
+
+	// Delimiters skipped before the [DeptCourse] Field, allowed between [Dept] and [Course] and
+	// between [Year] and [Semester]. Any Unicode space matches a ' ' delimiter.
+	Delimiters string
+
+	// LenientSeparators accepts any run of Delimiters and Field Seperators between [DeptCourse]
+	// and [OfferSession], instead of exactly one FieldSeparator
+	LenientSeparators bool
+
+	// Semester Lookup Dictionary used by validateSemester()
+	Semesters *SemesterDict
+
+	// Locale of the [Semester] names accepted besides the Semesters, e.g. "es" for "Otoño":
+	// one of SemesterLocales, AutoLocale for all of them, or "" for none (see locales.go)
+	Locale string
+
+	// First term of an academic year "AY 2019-20", which runs up to the term before it in the next year
+	AcademicYearStart string
+
+	// Institutional term codes accepted as the [OfferSession] (e.g. Banner "201990"), none by
+	// default. The first one formats CourseSelection.TermCode. A 2 digit year in a term
+	// code (compact "F98") is expanded around Years.Pivot, like a [Year] token.
+	TermCodes []TermCodec
+
+	// Optional Course Catalog used to validate [Dept], [Course] and the [OfferSession]
+	Catalog *Catalog
+
+	// Valid range of years for Courses used by validateYear()
+	// Assumption : 2 digit year abbreviations are normalized around Years.Pivot,
+	// by default to the 21st centuary. e.g. Abbreviated year entry 89 will be 2089
+	Years YearWindow
+
+	// "Did you mean" suggestions for misspelled [Semester] and (with a Catalog) [Dept]
+	// tokens are offered up to SuggestDistance edits away. A misspelling with a single
+	// best suggestion at most AutoCorrect edits away is silently corrected (0 = never),
+	// but for a [Semester] leading the input, which may be a misread [Dept].
+	SuggestDistance int
+	AutoCorrect     int
+
+	// An input with several readings (e.g. "CS 2018 Fall") fails with ErrAmbiguous unless the
+	// best reading scores at least AmbiguityMargin above the next one
+	AmbiguityMargin float64
+
+	// Accepted input Layouts, tried in order (see DefaultLayouts)
+	Layouts []Layout
+
+	// Characters separating the entries of a ParseList() line
+	EntrySeparators string
+
+	// Recover keeps parsing after a failure and reports every problem of the input at once,
+	// as the Diagnostics of the ParseError, with a partial selection (see recover.go)
+	Recover bool
+
+	// Logger receives the funcid based trace (see trace.go), nil turns it off
+	Logger *slog.Logger
+
+	explain *recorder // set on the Parser copy of an Explain() call
+}
+
+// New returns a Parser with the default configuration
+func New() *Parser {
+	return &Parser{
+		FieldSeparator: DefaultFieldSeparator,
+		Delimiters:     DefaultDelimiters,
+		Semesters:      DefaultSemesters(),
+		Years:          DefaultYearWindow(),
+		Layouts:        DefaultLayouts(),
+
+		SuggestDistance: DefaultSuggestDistance,
+		AmbiguityMargin: DefaultAmbiguityMargin,
+		EntrySeparators: DefaultEntrySeparators,
+
+		AcademicYearStart: DefaultAcademicYearStart,
+	}
+}
+
+// Returns the Parser, or a copy of it whose unset fields take the defaults
+// of New(): a nil Semesters or Layouts, an empty EntrySeparators or
+// AcademicYearStart, a 0 FieldSeparator (with the Delimiters too, when they
+// are empty as well) and a Years window without bounds, which keeps its
+// Pivot and Clock. The other fields keep their zero value, e.g. no
+// suggestions for a 0 SuggestDistance.
+func (p *Parser) withDefaults() *Parser {
+	if p.Semesters != nil && p.Layouts != nil && p.FieldSeparator != 0 && p.Years.bounded() &&
+		p.EntrySeparators != "" && p.AcademicYearStart != "" {
+		return p
+	}
+
+	q := *p
+	if q.FieldSeparator == 0 {
+		q.FieldSeparator = DefaultFieldSeparator
+		if q.Delimiters == "" {
+			q.Delimiters = DefaultDelimiters
+		}
+	}
+	if q.Semesters == nil {
+		q.Semesters = DefaultSemesters()
+	}
+	if !q.Years.bounded() {
+		years := DefaultYearWindow()
+		years.Pivot, years.Clock = q.Years.Pivot, q.Years.Clock
+		q.Years = years
+	}
+	if q.Layouts == nil {
+		q.Layouts = DefaultLayouts()
+	}
+	if q.EntrySeparators == "" {
+		q.EntrySeparators = DefaultEntrySeparators
+	}
+	if q.AcademicYearStart == "" {
+		q.AcademicYearStart = DefaultAcademicYearStart
+	}
+	return &q
+}
+
+// Default Parser.FieldSeparator and Parser.Delimiters
+const DefaultFieldSeparator = ' '
+const DefaultDelimiters = " -:"
+
+// CheckSeparators reports a FieldSeparator or Delimiters the tokens could
+// not be told apart from, i.e. letters, digits or none at all
+func (p *Parser) CheckSeparators() error {
+	if p.FieldSeparator == 0 || !isSeparator(p.FieldSeparator) {
+		return fmt.Errorf("courseparser: invalid field separator %q", p.FieldSeparator)
+	}
+	for _, c := range p.Delimiters {
+		if !isSeparator(c) {
+			return fmt.Errorf("courseparser: invalid delimiter %q", c)
+		}
+	}
+	return nil
+}
+
+// Parser behind the package level Parse()
+var defaultParser = New()
+
+// Parse parses input with the default Parser configuration. See Parser.Parse.
+func Parse(input string) (CourseSelection, error) {
+	return defaultParser.Parse(input)
+}
+
+//===========================================================
+//=== String Parsing & Validation Utility functions =========
+//===========================================================
+
+//funcid:100
+func isLetter(c rune) bool {
+	return unicode.IsLetter(c) || unicode.Is(unicode.Mn, c)
+}
+
+//funcid:130
+func isNumber(c rune) bool {
+	if (c >= '0') && (c <= '9') {
+		return true
+	}
+	return false
+}
+
+//funcid:150
+func (p *Parser) isDelimiter(c rune) bool {
+	if strings.ContainsRune(p.Delimiters, c) || unicode.IsSpace(c) && strings.ContainsRune(p.Delimiters, ' ') {
+		return true
+	}
+	return false
+
+}
+
+//funcid:170
+func (p *Parser) isValid(c rune) bool {
+	if p.isDelimiter(c) || p.isFieldSeparator(c) || isNumber(c) || isLetter(c) {
+		return true
+	} else {
+		return false
+	}
+
+}
+
+// Reports whether c separates the [DeptCourse] and [OfferSession] Fields
+func (p *Parser) isFieldSeparator(c rune) bool {
+	return c == p.FieldSeparator || p.FieldSeparator == ' ' && unicode.IsSpace(c)
+}
+
+// Letters or digits, e.g. a term code
+func isAlphaNumeric(c rune) bool {
+	return isLetter(c) || isNumber(c)
+}
+
+// Letters and digits (and accents) make up the tokens, anything else may separate them
+func isSeparator(c rune) bool {
+	return !isLetter(c) && !isNumber(c)
+}
+
+//===========================================================
+//============ Common Token Parsing Functions ===============
+//===========================================================
+
+// Skips Spaces Delimiters by advancing "indx" along "data" string
+//
+//funcid:500
+func (p *Parser) skipSpacesDelims(inStr *ChStr) error {
+	var char rune
+
+	p.trace(LevelTrace, "500.10", "IN- : skipSpaceDelims()", inStr)
+
+	if inStr.indx < 0 || inStr.indx >= inStr.len {
+		return inStr.panicHere("500.20", "Invalid input structure")
+	}
+
+	for inStr.indx < inStr.len {
+		char = inStr.data[inStr.indx]
+		if !(p.isValid(char)) {
+			return inStr.errorHere("500.30", "Invalid Character ==> '"+string(char)+"' ", ErrInvalidCharacter, nil)
+		}
+
+		if p.isDelimiter(char) {
+			inStr.indx++
+		} else {
+			break
+		}
+	} // for
+
+	p.trace(LevelTrace, "500.90", "OUT : skipSpaceDelims()", inStr)
+
+	return nil
+}
+
+// Parses and Extracts a string of Alpha characters into a return "Token" string
+// funcid:600
+func (p *Parser) getAlphaToken(inStr *ChStr) (string, error) {
+	var alphaToken string
+	var char rune
+
+	p.trace(LevelTrace, "600.10", "IN- : getAlphaToken()", inStr)
+
+	if inStr.indx < 0 || inStr.indx >= inStr.len {
+		return "", inStr.panicHere("600.20", "Invalid input structure")
+	}
+
+	if !(isLetter(inStr.data[inStr.indx])) {
+		return "", inStr.errorHere("600.30", "Non Alpha first character in Alpha Token ==> '"+string(inStr.data[inStr.indx])+"' ", ErrInvalidCharacter, nil)
+	}
+
+	for inStr.indx < inStr.len {
+		char = inStr.data[inStr.indx]
+		if !(p.isValid(char)) {
+			return "", inStr.errorHere("600.40", "Invalid Character around Alpha token => '"+string(char)+"'", ErrInvalidCharacter, nil)
+		}
+
+		if isLetter(char) {
+			alphaToken += string(char)
+			inStr.indx++
+			continue
+		} else {
+			break
+		} // if-else
+	} // For Loop - Semester Parser
+
+	p.trace(LevelTrace, "600.90", "OUT : getAlphaToken()", inStr, "token", alphaToken)
+
+	return alphaToken, nil
+
+}
+
+// Parses and Extract a string of Numeric characters into a return "Token" string
+// funcid:650
+func (p *Parser) getNumberToken(inStr *ChStr) (string, error) {
+	var numberToken string
+	var char rune
+
+	p.trace(LevelTrace, "650.10", "IN- : getNumberToken()", inStr)
+
+	if inStr.indx < 0 || inStr.indx >= inStr.len {
+		return "", inStr.panicHere("650.20", "Invalid input structure")
+	}
+
+	if !(isNumber(inStr.data[inStr.indx])) {
+		return "", inStr.errorHere("650.30", "Non Number first character in Number Token ==> '"+string(inStr.data[inStr.indx])+"'", ErrInvalidCharacter, nil)
+	}
+
+	for inStr.indx < inStr.len {
+		char = inStr.data[inStr.indx]
+		if !(p.isValid(char)) {
+			return "", inStr.errorHere("650.40", "Invalid Character around Number token => '"+string(char)+"'", ErrInvalidCharacter, nil)
+		}
+
+		if isNumber(char) {
+			numberToken += string(char)
+			inStr.indx++
+			continue
+		} else {
+			break
+		} // if-else
+	} // For Loop - Semester Parser
+
+	p.trace(LevelTrace, "650.90", "OUT : getNumberToken()", inStr, "token", numberToken)
+
+	return numberToken, nil
+
+}
+
+//====================================================================
+//============ Course Selection Solution specific Functions ==========
+//      Functions to parse [DeptCourse] and [OfferSession] Fields
+//====================================================================
+
+// Function to parse input string and Process  [DeptCourse] Field and
+// update the selection, as listed in DeptCourseRules()
+
+//funcid:700
+func (p *Parser) getDeptCourse(inStr *ChStr, sel *CourseSelection) error {
+
+	p.trace(slog.LevelDebug, "700.10", "IN- : getDeptCourse()", inStr)
+
+	err := p.runRule(inStr, sel, &deptCourseRule)
+	if err != nil {
+		return err
+	}
+
+	p.trace(slog.LevelDebug, "700.90", "OUT : getDeptCourse()", inStr, "dept", sel.Dept, "course", sel.CourseCode())
+
+	return nil
+
+}
+
+var deptCourseRule = Rule{Name: "[DeptCourse]", Seq: DeptCourseRules(), Missing: "700.40", Expect: "700.50"}
+
+// Parse and Extract the Department Token for the [DeptCourse] Field
+//
+//funcid:720
+func (p *Parser) getDeptToken(inStr *ChStr, sel *CourseSelection) error {
+	var retToken string
+	var err error
+
+	p.trace(slog.LevelDebug, "720.10", "IN- : getDeptToken()", inStr)
+
+	start := inStr.indx
+	retToken, err = p.getAlphaToken(inStr)
+	if err != nil {
+		return inStr.errorHere("720.30", "When Getting Department data ", ErrInvalidDept, err)
+	}
+
+	if p.Catalog != nil {
+		dept, inCatalog := p.Catalog.Dept(retToken)
+		if !inCatalog {
+			suggestions := p.SuggestDept(retToken)
+			dept, inCatalog = p.autoCorrection(suggestions)
+			if !inCatalog {
+				pe := inStr.errorAt("720.40", start, "Unknown Department "+retToken+didYouMean(suggestions), ErrUnknownDept, nil)
+				pe.Suggestions = suggestions
+				return pe
+			}
+			sel.Corrections = append(sel.Corrections, Correction{Token: "Dept", From: retToken, To: dept})
+		}
+		retToken = dept
+	}
+
+	sel.Dept += retToken
+
+	p.trace(slog.LevelDebug, "720.90", "OUT : getDeptToken()", inStr, "token", retToken, "dept", sel.Dept)
+
+	return nil
+
+}
+
+// Parse and Extract the Course Token for the [DeptCourse] Field
+// funcid:750
+func (p *Parser) getCourseToken(inStr *ChStr, sel *CourseSelection) error {
+	var retToken string
+	var err error
+
+	p.trace(slog.LevelDebug, "750.10", "IN- : getCourseToken()", inStr)
+
+	start := inStr.indx
+	retToken, err = p.getNumberToken(inStr)
+	if err != nil {
+		return inStr.errorHere("750.30", "When Getting Course data ", ErrInvalidCourse, err)
+	}
+
+	sel.Course += retToken
+
+	// Optional letter Suffix, e.g. 111L, 101H
+	if inStr.indx < inStr.len && isLetter(inStr.data[inStr.indx]) {
+		err = p.getSuffixToken(inStr, sel)
+		if err != nil {
+			return inStr.errorHere("750.50", "After Parsing Course number "+retToken, nil, err)
+		}
+	}
+
+	// Optional Section, e.g. 111-02
+	if inStr.indx+1 < inStr.len && inStr.data[inStr.indx] == SectionSeparator && isNumber(inStr.data[inStr.indx+1]) {
+		inStr.indx++
+		err = p.getSectionToken(inStr, sel)
+		if err != nil {
+			return inStr.errorHere("750.60", "After Parsing Course "+retToken+sel.Suffix, nil, err)
+		}
+	}
+
+	if p.Catalog != nil && p.catalogCourse(sel) == "" {
+		return inStr.errorAt("750.40", start, "Unknown Course "+sel.Dept+" "+sel.CourseCode(), ErrUnknownCourse, nil).with("course", sel.Dept+" "+sel.CourseCode())
+	}
+
+	p.trace(slog.LevelDebug, "750.90", "OUT : getCourseToken()", inStr, "token", retToken, "course", sel.CourseCode())
+
+	return nil
+
+}
+
+// Parse and Extract the optional letter Suffix of the [Course] Token
+// funcid:755
+func (p *Parser) getSuffixToken(inStr *ChStr, sel *CourseSelection) error {
+	var retToken string
+	var err error
+
+	p.trace(LevelTrace, "755.10", "IN- : getSuffixToken()", inStr)
+
+	start := inStr.indx
+	retToken, err = p.getAlphaToken(inStr)
+	if err != nil {
+		return inStr.errorAt("755.30", start, "When Getting Course suffix ", ErrInvalidSuffix, err)
+	}
+
+	if utf8.RuneCountInString(retToken) > MaxSuffixLen {
+		return inStr.errorAt("755.40", start, "Course suffix '"+retToken+"' longer than "+strconv.Itoa(MaxSuffixLen)+" letters", ErrInvalidSuffix, nil).with("max", strconv.Itoa(MaxSuffixLen))
+	}
+
+	sel.Suffix = strings.ToUpper(retToken)
+
+	p.trace(LevelTrace, "755.90", "OUT : getSuffixToken()", inStr, "token", retToken)
+
+	return nil
+}
+
+// Parse and Extract the optional Section number of the [Course] Token
+// funcid:760
+func (p *Parser) getSectionToken(inStr *ChStr, sel *CourseSelection) error {
+	var retToken string
+	var err error
+
+	p.trace(LevelTrace, "760.10", "IN- : getSectionToken()", inStr)
+
+	start := inStr.indx
+	retToken, err = p.getNumberToken(inStr)
+	if err != nil {
+		return inStr.errorAt("760.30", start, "When Getting Section number ", ErrInvalidSection, err)
+	}
+
+	if len(retToken) > MaxSectionLen {
+		return inStr.errorAt("760.40", start, "Section number '"+retToken+"' longer than "+strconv.Itoa(MaxSectionLen)+" digits", ErrInvalidSection, nil).with("max", strconv.Itoa(MaxSectionLen))
+	}
+
+	sel.Section = retToken
+
+	p.trace(LevelTrace, "760.90", "OUT : getSectionToken()", inStr, "token", retToken)
+
+	return nil
+}
+
+// ========================================================================
+// Function to parse input string and Process  [OfferSession] Field and
+// update the selection
+// NOTE: Handles both [Semester-Year] as well as [Year-Semester] formats,
+// as listed in OfferSessionRules()
+// =======================================================================
+//
+//funcid:800
+func (p *Parser) getOfferSession(inStr *ChStr, sel *CourseSelection) error {
+
+	p.trace(slog.LevelDebug, "800.10", "IN- : getOfferSession()", inStr)
+
+	err := p.runRule(inStr, sel, &offerSessionRule)
+	if err != nil {
+		return err
+	}
+
+	p.trace(slog.LevelDebug, "800.90", "OUT : getOfferSession()", inStr, "semester", sel.Semester, "year", sel.Year)
+
+	return nil
+
+}
+
+var offerSessionRule = Rule{Name: "[OfferSession]", Alt: OfferSessionRules(), Missing: "800.20", Expect: "800.15"}
+
+// Parse and Extract the Year Token for the [OfferSession] Field
+// funcid:920
+func (p *Parser) getYearToken(inStr *ChStr, sel *CourseSelection) error {
+	var retToken string
+	var err error
+
+	p.trace(slog.LevelDebug, "920.10", "IN- : getYearToken()", inStr)
+
+	start := inStr.indx
+	retToken, err = p.getNumberToken(inStr)
+	if err != nil {
+		return inStr.errorAt("920.30", start, "When Getting Year data "+retToken, ErrInvalidYear, err)
+	}
+
+	sel.Year, err = p.validateYear(retToken)
+	if err != nil {
+		return inStr.errorAt("920.35", start, "Invalid Year.  Or Course not offered for Year "+retToken, nil, err)
+	}
+
+	p.trace(slog.LevelDebug, "920.90", "OUT : getYearToken()", inStr, "token", retToken, "year", sel.Year)
+
+	return nil
+
+}
+
+// Parse and Extract the Semester Token for the [OfferSession] Field
+//
+//funcid:950
+func (p *Parser) getSemesterToken(inStr *ChStr, sel *CourseSelection) error {
+	var retToken string
+	var err error
+
+	p.trace(slog.LevelDebug, "950.10", "IN- : getSemesterToken()", inStr)
+
+	start := inStr.indx
+	retToken, err = p.getAlphaToken(inStr)
+	if err != nil {
+		return inStr.errorAt("950.30", start, "When Getting Semester data ", ErrInvalidSemester, err)
+	}
+
+	sel.Semester, sel.Locale, err = p.validateSemester(strings.ToUpper(retToken))
+	if err != nil {
+		// A leading alpha token is a [Semester] only when it is in the dictionary,
+		// else it may well be the [Dept] of another layout ("CS 2020 MATH 101")
+		semester, corrected := "", false
+		if !inStr.leading(p, start) {
+			semester, corrected = p.autoCorrection(Suggestions(err))
+		}
+		if !corrected {
+			return inStr.errorAt("950.35", start, "Invalid Semester Entry "+retToken, nil, err)
+		}
+		sel.Semester = semester
+		sel.Corrections = append(sel.Corrections, Correction{Token: "Semester", From: retToken, To: semester})
+	}
+
+	p.trace(slog.LevelDebug, "950.90", "OUT : getSemesterToken()", inStr, "token", retToken, "semester", sel.Semester)
+
+	return nil
+
+}
+
+// Validate Course Offer Year  using a Year Range Validator
+//
+//funcid:970
+func (p *Parser) validateYear(yearStr string) (int, error) {
+
+	p.trace(slog.LevelDebug, "970.10", "IN- : validateYear()", nil, "token", yearStr)
+	numYear, errGO := strconv.Atoi(yearStr)
+	if errGO != nil {
+		return 0, &ParseError{Code: "970.20", Offset: -1, Msg: "Invalid Year Input " + yearStr, Kind: ErrInvalidYear, Err: errGO}
+	} // if (errGO != nil)
+
+	// fix Year abbreviation
+	numYear = p.Years.Expand(numYear, len(yearStr))
+
+	if !p.Years.Contains(numYear) {
+		earliest, latest := p.Years.Bounds()
+		pe := &ParseError{Code: "970.70", Offset: -1, Msg: "Invalid Year Range " + strconv.Itoa(numYear) + " (valid " + strconv.Itoa(earliest) + " - " + strconv.Itoa(latest) + ")", Kind: ErrYearOutOfRange}
+		return 0, pe.with("year", strconv.Itoa(numYear), "earliest", strconv.Itoa(earliest), "latest", strconv.Itoa(latest))
+	}
+
+	p.trace(slog.LevelDebug, "970.90", "OUT : validateYear()", nil, "year", numYear)
+
+	return numYear, nil
+}
+
+// Validate Semester using a Semester Dictionary (and the Parser's locales),
+// returning the canonical term and the locale it was named in
+// funcid:975
+func (p *Parser) validateSemester(semesterStr string) (string, string, error) {
+	var semester, locale string
+	var inMap bool
+
+	p.trace(slog.LevelDebug, "975.10", "IN- : validateSemester()", nil, "token", semesterStr)
+	semester, locale, inMap = p.lookupSemester(semesterStr)
+	if !(inMap) {
+		suggestions := p.SuggestSemester(semesterStr)
+		return "", "", &ParseError{Code: "975.15", Offset: -1, Msg: "Invalid Semester lookup " + semesterStr + didYouMean(suggestions), Kind: ErrInvalidSemester, Suggestions: suggestions}
+	}
+
+	p.trace(slog.LevelDebug, "975.90", "OUT : validateSemester()", nil, "semester", semester, "locale", locale)
+	return semester, locale, nil
+}
+
+// Validate the Course is offered in the Semester and Year (or in any term of
+// a term range) using the Course Catalog
+// funcid:980
+func (p *Parser) validateOffering(sel *CourseSelection) error {
+
+	p.trace(slog.LevelDebug, "980.10", "IN- : validateOffering()", nil, "tokens", sel.Tokens())
+
+	if sel.Range != nil {
+		for _, term := range sel.Range.Terms {
+			if p.Catalog.Offered(sel.Dept, p.catalogCourse(sel), term.Semester, term.Year) {
+				return nil
+			}
+		}
+		pe := &ParseError{Code: "980.30", Offset: -1, Msg: sel.Dept + " " + sel.CourseCode() + " is not offered from " + sel.Range.Start.String() + " to " + sel.Range.End.String(), Kind: ErrCourseNotOffered}
+		return pe.with("course", sel.Dept+" "+sel.CourseCode(), "start", sel.Range.Start.String(), "end", sel.Range.End.String())
+	}
+
+	if !p.Catalog.Offered(sel.Dept, p.catalogCourse(sel), sel.Semester, sel.Year) {
+		pe := &ParseError{Code: "980.20", Offset: -1, Msg: sel.Dept + " " + sel.CourseCode() + " is not offered in " + sel.Semester + " " + strconv.Itoa(sel.Year), Kind: ErrCourseNotOffered}
+		return pe.with("course", sel.Dept+" "+sel.CourseCode(), "term", sel.Term().String())
+	}
+
+	p.trace(slog.LevelDebug, "980.90", "OUT : validateOffering()", nil)
+	return nil
+}
+
+// Checks the Course is offered in the Session, once the Fields hold both.
+// matchLayout() runs it on the selection of whichever layout matched.
+func (p *Parser) checkOffering(sel *CourseSelection) error {
+	if p.Catalog == nil || sel.Course == "" || sel.Semester == "" {
+		return nil
+	}
+	return p.validateOffering(sel)
+}
+
+//=====================================================================
+// Function Parse() - Contains Primary Parser for Input String
+//=====================================================================
+
+// Parse tokenizes and validates one "Course Selection input text" and
+// returns the [DeptCourse] and [OfferSession] tokens it found, matching the
+// input against the Parser's Layouts. On error the returned selection holds
+// whichever tokens were parsed before the failure and the error is a
+// *ParseError stack (see Locate for the failure position). With Recover
+// set the selection holds every valid token and the error lists all the
+// problems found as its Diagnostics.
+//
+//funcid:1000
+func (p *Parser) Parse(input string) (CourseSelection, error) {
+	var err error
+	var sel CourseSelection
+
+	p = p.withDefaults()
+
+	// Setup INPUT Data Structures
+	inStr := newChStr(input)
+
+	p.trace(slog.LevelInfo, "1000.100", "IN- : Parse()", &inStr)
+
+	// =====================================================================
+	// Skip Leading Spaces and Delimiters
+	// =====================================================================
+
+	if inStr.len == 0 {
+		err = inStr.errorHere("1000.107", "No Input Data Found", ErrEmptyInput, nil)
+		goto ExitParse
+	}
+
+	err = p.skipSpacesDelims(&inStr)
+
+	if err != nil {
+		err = inStr.errorHere("1000.150", "at start of input string "+string(inStr.src), nil, err)
+		goto ExitParse
+	} // if (err != nil)
+
+	if inStr.indx >= inStr.len {
+		err = inStr.errorHere("1000.108", "No Input Data Found, only delimiters", ErrEmptyInput, nil)
+		goto ExitParse
+	}
+
+	// =====================================================================
+	// Process the [DeptCourse] and [OfferSession] Fields of an input Layout
+	// =====================================================================
+	err = p.matchLayout(&inStr, &sel)
+
+	p.trace(slog.LevelDebug, "1000.250", "MID : Parse() Result After Parsing the Fields", &inStr, "tokens", sel.Tokens())
+
+	if err == nil && len(inStr.diags) > 0 {
+		dropOpenRange(&sel)
+		err = diagnosticsError(inStr.diags)
+		goto ExitParse
+	}
+
+ExitParse:
+	// "CS 2018 Fall" : is 2018 the course or the year?
+	// (an input with several problems is reported as is)
+	if len(inStr.diags) <= 1 && p.courseLooksLikeYear(sel) {
+		p.enterStep("resolveAmbiguity()", 0)
+		sel, err = p.resolveAmbiguity(input, sel, err)
+		p.leaveStep(len(inStr.src), joinTokens(sel.Tokens()), err)
+	}
+
+	if sel.Confidence == 0 {
+		sel.Confidence = 1
+	}
+	if err == nil {
+		p.encodeTerms(&sel)
+	}
+
+	p.trace(slog.LevelInfo, "1000.900", "OUT : Parse()", nil, "tokens", sel.Tokens(), "layout", sel.Layout, "ok", err == nil)
+
+	return sel, err
+
+} // Parse
@@ -0,0 +1,133 @@
+package courseparser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseSuffixSection(t *testing.T) {
+	tests := []struct {
+		input   string
+		suffix  string
+		section string
+		code    string // CourseCode(), "" when the input must fail
+		kind    error
+		offset  int
+	}{
+		{"CS 111L Fall 2019", "L", "", "111L", nil, 0},
+		{"CS 111lh Fall 2019", "LH", "", "111LH", nil, 0},
+		{"CS 111-02 Fall 2019", "", "02", "111-02", nil, 0},
+		{"CS-111-1 Fall 2019", "", "1", "111-1", nil, 0},
+		{"CS 111h-002 Fall 2019", "H", "002", "111H-002", nil, 0},
+		{"CS 111LAB Fall 2019", "", "", "", ErrInvalidSuffix, 6},
+		{"CS 111-0001 Fall 2019", "", "", "", ErrInvalidSection, 7},
+		{"CS 111- Fall 2019", "", "", "", ErrMissingFieldSeparator, 6},    // a section needs digits
+		{"CS 111-02L Fall 2019", "", "", "", ErrMissingFieldSeparator, 9}, // the suffix comes first
+		{"CS 111 L Fall 2019", "", "", "", ErrInvalidSemester, 7},         // and is not a token of its own
+	}
+
+	p := testParser()
+	for _, test := range tests {
+		sel, err := p.Parse(test.input)
+		if test.code == "" {
+			if pe := Locate(err); !errors.Is(err, test.kind) || pe == nil || pe.Offset != test.offset {
+				t.Errorf("%q: error %v at %+v, want %v at %d", test.input, err, pe, test.kind, test.offset)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", test.input, err)
+			continue
+		}
+		if sel.Course != "111" || sel.Suffix != test.suffix || sel.Section != test.section || sel.CourseCode() != test.code {
+			t.Errorf("%q: course %q suffix %q section %q code %q, want 111 %q %q %q", test.input, sel.Course, sel.Suffix, sel.Section, sel.CourseCode(), test.suffix, test.section, test.code)
+		}
+	}
+}
+
+// A catalog may list a course with its suffix, else the plain number stands for it
+func TestCatalogSuffix(t *testing.T) {
+	p := testParser()
+	p.Catalog = NewCatalog()
+	p.Catalog.AddCourse("CS", "111")
+	p.Catalog.AddCourse("CS", "112L", Offering{Semester: "Spring"})
+
+	for _, test := range []struct {
+		input string
+		kind  error
+	}{
+		{"CS 111L-02 Fall 2019", nil},
+		{"CS 112L Spring 2019", nil},
+		{"CS 112L Fall 2019", ErrCourseNotOffered},
+		{"CS 112 Spring 2019", ErrUnknownCourse},
+		{"CS 112H Spring 2019", ErrUnknownCourse},
+	} {
+		if _, err := p.Parse(test.input); !errors.Is(err, test.kind) {
+			t.Errorf("%q: error %v, want %v", test.input, err, test.kind)
+		}
+	}
+}
+
+func TestSeparators(t *testing.T) {
+	tests := []struct {
+		fieldSep   rune
+		delimiters string
+		lenient    bool
+		input      string
+		kind       error // nil when the input parses
+		offset     int
+	}{
+		{'|', " ", false, "CS 111|Fall 2019", nil, 0},
+		{'|', " ", false, "CS-111|Fall 2019", ErrInvalidCharacter, 2},
+		{'|', " ", false, "CS 111 | Fall 2019", ErrMissingFieldSeparator, 6},
+		{'|', " ", true, "CS 111 | Fall 2019", nil, 0},
+		{'|', "", false, "CS 111|Fall 2019", ErrInvalidCharacter, 2},
+		{'/', "_", false, "CS_111/Fall_2019", nil, 0},
+		{'/', "_", false, "CS_111/Fall 2019", ErrInvalidCharacter, 11},
+		{'\t', " ", false, "CS 111\tFall 2019", nil, 0},
+		{'\t', " ", false, "CS 111 Fall 2019", ErrMissingFieldSeparator, 6},
+		{' ', DefaultDelimiters, false, "::CS:111 Fall:2019", nil, 0},
+		{' ', DefaultDelimiters, false, "CS 111  Fall 2019", ErrExtraDelimiter, 7},
+		{' ', DefaultDelimiters, false, "CS 111 - Fall 2019", ErrExtraDelimiter, 7},
+		{' ', DefaultDelimiters, true, "CS 111  Fall 2019", nil, 0},
+		{' ', DefaultDelimiters, true, "CS 111 -:- Fall - 2019", nil, 0},
+	}
+
+	for _, test := range tests {
+		p := testParser()
+		p.FieldSeparator, p.Delimiters, p.LenientSeparators = test.fieldSep, test.delimiters, test.lenient
+		sel, err := p.Parse(test.input)
+		if test.kind == nil {
+			if err != nil || joinTokens(sel.Tokens()) != "CS 111 2019 Fall" {
+				t.Errorf("%q %q lenient %v: %q: %v %v", test.fieldSep, test.delimiters, test.lenient, test.input, sel.Tokens(), err)
+			}
+			continue
+		}
+		if pe := Locate(err); !errors.Is(err, test.kind) || pe == nil || pe.Offset != test.offset {
+			t.Errorf("%q %q lenient %v: %q: error %v at %+v, want %v at %d", test.fieldSep, test.delimiters, test.lenient, test.input, err, pe, test.kind, test.offset)
+		}
+	}
+}
+
+func TestCheckSeparators(t *testing.T) {
+	tests := []struct {
+		fieldSep   rune
+		delimiters string
+		ok         bool
+	}{
+		{' ', DefaultDelimiters, true},
+		{'\t', " _", true},
+		{'|', "", true},
+		{0, "", false},
+		{'a', "", false},
+		{'1', "", false},
+		{'|', "-x", false},
+		{'|', "\u0301", false}, // accents are a part of their letter
+	}
+	for _, test := range tests {
+		p := Parser{FieldSeparator: test.fieldSep, Delimiters: test.delimiters}
+		if err := p.CheckSeparators(); (err == nil) != test.ok {
+			t.Errorf("%q %q: CheckSeparators() = %v", test.fieldSep, test.delimiters, err)
+		}
+	}
+}
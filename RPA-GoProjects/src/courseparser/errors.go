@@ -0,0 +1,144 @@
+package courseparser
+
+import (
+	"errors"
+	"strconv"
+)
+
+//===========================================================
+//============ Structured Parse Errors ======================
+//===========================================================
+
+// Failure kinds. Every ParseError in a stack may carry one of these as its
+// Kind so callers can test for specific failures with errors.Is.
+var (
+	ErrInvalidInput          = errors.New("invalid input structure")
+	ErrEmptyInput            = errors.New("no input data")
+	ErrInvalidCharacter      = errors.New("invalid character")
+	ErrMissingDept           = errors.New("missing department")
+	ErrInvalidDept           = errors.New("invalid department")
+	ErrMissingCourse         = errors.New("missing course")
+	ErrInvalidCourse         = errors.New("invalid course")
+	ErrInvalidSuffix         = errors.New("invalid course suffix")
+	ErrInvalidSection        = errors.New("invalid section")
+	ErrMissingFieldSeparator = errors.New("missing field separator")
+	ErrExtraDelimiter        = errors.New("more than one delimiter between fields")
+	ErrMissingSession        = errors.New("missing offer session")
+	ErrMissingSemester       = errors.New("missing semester")
+	ErrMissingYear           = errors.New("missing year")
+	ErrInvalidSemester       = errors.New("invalid semester")
+	ErrInvalidYear           = errors.New("invalid year")
+	ErrYearOutOfRange        = errors.New("year out of range")
+	ErrUnknownDept           = errors.New("unknown department")
+	ErrUnknownCourse         = errors.New("unknown course")
+	ErrCourseNotOffered      = errors.New("course not offered in term")
+	ErrTrailingData          = errors.New("unexpected data after offer session")
+	ErrInvalidRange          = errors.New("invalid term range")
+	ErrInvalidTermCode       = errors.New("invalid term code")
+	ErrAmbiguous             = errors.New("ambiguous input")
+)
+
+// ParseError is one level of the funcid based error stack. The wrapped Err
+// is the next (deeper) level, so the whole stack is walked with errors.Unwrap
+// and printed by Error() in the same "ERROR-xxx.yy - ..." layout as before.
+type ParseError struct {
+	Code   string // funcid code, e.g. "950.35"
+	Panic  bool   // internal invariant failure (PANIC-) rather than a data entry error (ERROR-)
+	Offset int    // rune offset into the input where the error was detected, -1 if unknown
+	Column int    // terminal column of Offset (see Column), valid when Offset >= 0
+	Char   rune   // offending character at Offset as typed, 0 if none
+	Msg    string
+	Kind   error // one of the Err* failure kinds, nil for plain stack frames
+	Err    error // wrapped cause
+
+	Suggestions []Suggestion     // "did you mean" candidates for a misspelled token, best first
+	Candidates  []Interpretation // readings of an ErrAmbiguous input, best first
+	Diagnostics []Diagnostic     // every problem of the input found by a recovering Parse, in input order
+
+	Args map[string]string // parameters of the user message template, e.g. "token" (see Messages)
+}
+
+// Error renders the error stack from this level down
+func (e *ParseError) Error() string {
+	var msg string
+
+	if e.Panic {
+		msg = "PANIC-"
+	} else {
+		msg = "ERROR-"
+	}
+	msg += e.Code + " - " + e.Msg
+	if e.Err != nil {
+		msg += " \n " + e.Err.Error()
+	}
+	for _, d := range e.Diagnostics {
+		msg += " \n " + d.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the next level of the error stack
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether this level of the stack, or any of its Diagnostics, is
+// of the failure kind target
+func (e *ParseError) Is(target error) bool {
+	if e.Kind != nil && e.Kind == target {
+		return true
+	}
+	for _, d := range e.Diagnostics {
+		if errors.Is(d.Err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Locate returns the deepest ParseError in err's chain that carries an input
+// position, i.e. the most precise location of the failure. It returns nil
+// when err holds no positioned ParseError.
+func Locate(err error) *ParseError {
+	var found *ParseError
+
+	for err != nil {
+		pe, ok := err.(*ParseError)
+		if ok && pe.Offset >= 0 {
+			found = pe
+		}
+		err = errors.Unwrap(err)
+
+		// The first problem found locates a recovering Parse's error
+		if ok && err == nil && len(pe.Diagnostics) > 0 {
+			err = pe.Diagnostics[0].Err
+		}
+	}
+	return found
+}
+
+// Builds a ParseError positioned at rune index "offset" along the "data" string
+func (inStr *ChStr) errorAt(code string, offset int, msg string, kind error, cause error) *ParseError {
+	pe := &ParseError{Code: code, Offset: inStr.srcOffset(offset), Msg: msg, Kind: kind, Err: cause}
+
+	if pe.Offset >= 0 {
+		pe.Column = Column(string(inStr.src), pe.Offset)
+		if pe.Offset < len(inStr.src) {
+			pe.Char = inStr.src[pe.Offset]
+			pe.with("token", wordAt(inStr.src, pe.Offset), "char", string(pe.Char))
+		}
+	}
+	return pe
+}
+
+// Builds a ParseError at the current "indx" position
+func (inStr *ChStr) errorHere(code string, msg string, kind error, cause error) *ParseError {
+	return inStr.errorAt(code, inStr.indx, msg, kind, cause)
+}
+
+// Builds a PANIC ParseError for an invalid input structure
+func (inStr *ChStr) panicHere(code string, msg string) *ParseError {
+	pe := inStr.errorAt(code, inStr.indx, msg+" ==> '"+strconv.Itoa(inStr.indx)+"'", ErrInvalidInput, nil)
+	pe.Panic = true
+	return pe
+}
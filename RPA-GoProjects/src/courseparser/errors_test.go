@@ -0,0 +1,90 @@
+package courseparser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestErrorKinds(t *testing.T) {
+	tests := []struct {
+		input string
+		kind  error
+		code  string // code of the deepest positioned error
+	}{
+		{"", ErrEmptyInput, "1000.107"},
+		{"CS#111 Fall 2019", ErrInvalidCharacter, ""},
+		{"CS 111 Fxll 2019", ErrInvalidSemester, "950.35"},
+		{"CS 111 Fall 1990", ErrYearOutOfRange, ""},
+		{"CS 111 Fall 2019 x", ErrTrailingData, ""},
+		{"CS 111", ErrMissingFieldSeparator, ""},
+		{"CS 111 2019", ErrMissingSemester, ""},
+		{"CS 111LABS Fall 2019", ErrInvalidSuffix, "755.40"},
+		{"CS 111-0001 Fall 2019", ErrInvalidSection, "760.40"},
+		{"CS 111 Spring 2020 - Fall 2019", ErrInvalidRange, ""},
+	}
+
+	p := testParser()
+	for _, test := range tests {
+		_, err := p.Parse(test.input)
+		if !errors.Is(err, test.kind) {
+			t.Errorf("%q: error %v, want %v", test.input, err, test.kind)
+			continue
+		}
+
+		var pe *ParseError
+		if !errors.As(err, &pe) || !strings.HasPrefix(err.Error(), "ERROR-"+pe.Code+" - ") {
+			t.Errorf("%q: errors.As = %+v, want the outermost ParseError", test.input, pe)
+		}
+		if test.code != "" {
+			if loc := Locate(err); loc == nil || loc.Code != test.code {
+				t.Errorf("%q: Locate = %+v, want code %s", test.input, loc, test.code)
+			}
+		}
+		for _, other := range []error{ErrAmbiguous, ErrUnknownCourse, ErrInvalidTermCode} {
+			if errors.Is(err, other) {
+				t.Errorf("%q: error is %v too", test.input, other)
+			}
+		}
+	}
+}
+
+// Every level of the stack renders as an "ERROR-" line, outermost first
+func TestParseErrorStack(t *testing.T) {
+	_, err := testParser().Parse("CS 111 Fxll 2019")
+
+	lines := strings.Split(err.Error(), "\n")
+	if len(lines) < 2 {
+		t.Fatalf("Error() = %q, want a stack", err.Error())
+	}
+	codes := []string{}
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		pe, ok := e.(*ParseError)
+		if !ok {
+			t.Fatalf("%T in the stack, want *ParseError", e)
+		}
+		codes = append(codes, pe.Code)
+	}
+	if len(codes) != len(lines) {
+		t.Errorf("%d levels for %d lines", len(codes), len(lines))
+	}
+	for i, line := range lines {
+		if !strings.HasPrefix(strings.TrimSpace(line), "ERROR-"+codes[i]+" - ") {
+			t.Errorf("line %d %q, want code %s", i, line, codes[i])
+		}
+	}
+	if codes[len(codes)-1] != "975.15" {
+		t.Errorf("deepest code %s, want 975.15", codes[len(codes)-1])
+	}
+}
+
+func TestPanicError(t *testing.T) {
+	p := testParser()
+	p.Layouts = []Layout{}
+
+	_, err := p.Parse("CS 111 Fall 2019")
+	var pe *ParseError
+	if !errors.As(err, &pe) || !errors.Is(err, ErrInvalidInput) || !strings.Contains(err.Error(), "PANIC-") {
+		t.Errorf("empty Layouts: %v, want a PANIC %v", err, ErrInvalidInput)
+	}
+}
@@ -0,0 +1,195 @@
+package courseparser
+
+import (
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+//===========================================================
+//============ Explain Mode =================================
+//===========================================================
+// Explain() parses an input like Parse() and also returns the walk of the
+// parser as a tree of Steps: the layouts tried, every grammar rule run
+// (Fields, tokens and separators) with the span of input it consumed and
+// the token it produced, and the funcid trace records (see trace.go) of the
+// functions entered inside each step, e.g. the validation decisions.
+// A failed step carries the most specific error of its failure, so the
+// tree shows exactly why an entry was rejected.
+
+// Step is one node of the parse tree returned by Explain
+type Step struct {
+	Name  string `json:"name"`            // rule, layout or function, e.g. "[Semester]"
+	Start int    `json:"start"`           // rune offset into the input as typed where the step began
+	End   int    `json:"end"`             // rune offset where the step stopped
+	Span  string `json:"span"`            // input consumed by the step, as typed
+	Token string `json:"token,omitempty"` // token produced by a token rule, e.g. "Fall"
+	Error string `json:"error,omitempty"` // most specific error, when the step failed
+
+	Events []Event `json:"events,omitempty"` // funcid trace records inside the step
+	Steps  []*Step `json:"steps,omitempty"`
+}
+
+// OK reports whether the step matched
+func (s *Step) OK() bool {
+	return s.Error == ""
+}
+
+// Event is one funcid trace record, e.g. "950.90 OUT : getSemesterToken() semester=Fall"
+type Event struct {
+	Funcid string            `json:"funcid"`
+	Msg    string            `json:"msg"`
+	Offset int               `json:"offset"` // cursor position, -1 when the function has none
+	Attrs  map[string]string `json:"attrs,omitempty"`
+}
+
+// Explain parses input (see Parse) and returns the parse tree, rooted at
+// a "Parse()" step spanning the whole input
+func (p *Parser) Explain(input string) (*Step, CourseSelection, error) {
+	// A copy of the Parser carries the recorder, so p may still be shared
+	q := *p
+	q.explain = &recorder{src: []rune(input)}
+
+	q.explain.enter("Parse()", 0)
+	sel, err := q.Parse(input)
+	q.explain.leave(len(q.explain.src), "", err)
+
+	root := q.explain.root
+	root.Token = joinTokens(sel.Tokens())
+	return root, sel, err
+}
+
+// Builds the Step tree while a Parser explains an input
+type recorder struct {
+	src   []rune
+	root  *Step
+	stack []*Step
+}
+
+// Opens a step at rune offset start
+func (r *recorder) enter(name string, start int) {
+	step := &Step{Name: name, Start: start}
+
+	if n := len(r.stack); n > 0 {
+		r.stack[n-1].Steps = append(r.stack[n-1].Steps, step)
+	} else {
+		r.root = step
+	}
+	r.stack = append(r.stack, step)
+}
+
+// Closes the innermost step at rune offset end
+func (r *recorder) leave(end int, token string, err error) {
+	n := len(r.stack)
+	if n == 0 {
+		return
+	}
+
+	step := r.stack[n-1]
+	r.stack = r.stack[:n-1]
+	if end < step.Start {
+		end = step.Start
+	}
+	if end > len(r.src) {
+		end = len(r.src)
+	}
+	step.End, step.Span = end, string(r.src[step.Start:end])
+
+	if err == nil {
+		step.Token = token
+		return
+	}
+	step.Error = err.Error()
+	if pe := deepest(err); pe != nil {
+		step.Error = pe.Code + " - " + pe.Msg
+	}
+}
+
+// Adds a trace record to the innermost step
+func (r *recorder) event(funcid string, msg string, inStr *ChStr, args []any) {
+	n := len(r.stack)
+	if n == 0 {
+		return
+	}
+
+	ev := Event{Funcid: funcid, Msg: msg, Offset: -1}
+	if inStr != nil {
+		ev.Offset = inStr.srcOffset(inStr.indx)
+	}
+
+	record := slog.NewRecord(time.Time{}, slog.LevelInfo, msg, 0)
+	record.Add(args...)
+	record.Attrs(func(a slog.Attr) bool {
+		if ev.Attrs == nil {
+			ev.Attrs = make(map[string]string)
+		}
+		ev.Attrs[a.Key] = a.Value.String()
+		return true
+	})
+	r.stack[n-1].Events = append(r.stack[n-1].Events, ev)
+}
+
+// Deepest ParseError of err's chain, positioned or not
+func deepest(err error) *ParseError {
+	var found *ParseError
+
+	for ; err != nil; err = errors.Unwrap(err) {
+		if pe, ok := err.(*ParseError); ok {
+			found = pe
+		}
+	}
+	return found
+}
+
+// Value of the token a token rule extracted into the selection
+func tokenValue(kind TokenKind, sel *CourseSelection) string {
+	switch kind {
+	case DeptToken:
+		return sel.Dept
+	case CourseToken:
+		return sel.CourseCode()
+	case YearToken:
+		if sel.Year != 0 {
+			return strconv.Itoa(sel.Year)
+		}
+	case SemesterToken:
+		return sel.Semester
+	case AcademicYearToken:
+		if sel.Range != nil {
+			return sel.Range.Start.String() + " - " + sel.Range.End.String()
+		}
+	case TermCodeToken:
+		return sel.Term().String()
+	}
+	return ""
+}
+
+func joinTokens(tokens []string) string {
+	var s string
+
+	for _, token := range tokens {
+		if token == "" {
+			continue
+		}
+		if s != "" {
+			s += " "
+		}
+		s += token
+	}
+	return s
+}
+
+// Explain mode hooks of the interpreter, no-ops unless the Parser explains.
+// Offsets are rune offsets into the input as typed.
+func (p *Parser) enterStep(name string, start int) {
+	if p.explain != nil {
+		p.explain.enter(name, start)
+	}
+}
+
+func (p *Parser) leaveStep(end int, token string, err error) {
+	if p.explain != nil {
+		p.explain.leave(end, token, err)
+	}
+}
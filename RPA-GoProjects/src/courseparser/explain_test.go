@@ -0,0 +1,119 @@
+package courseparser
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// Path of step names from the root to the first step named name, depth first
+func findStep(step *Step, name string) []*Step {
+	if step.Name == name {
+		return []*Step{step}
+	}
+	for _, sub := range step.Steps {
+		if path := findStep(sub, name); path != nil {
+			return append([]*Step{step}, path...)
+		}
+	}
+	return nil
+}
+
+func TestExplain(t *testing.T) {
+	p := testParser()
+
+	root, sel, err := p.Explain("CS 111 Fall 2019")
+	if err != nil || joinTokens(sel.Tokens()) != "CS 111 2019 Fall" {
+		t.Fatalf("Explain = %v %v", sel.Tokens(), err)
+	}
+	if root.Name != "Parse()" || root.Start != 0 || root.End != 16 || root.Token != "CS 111 2019 Fall" || !root.OK() {
+		t.Errorf("root %+v", root)
+	}
+
+	tests := []struct {
+		name        string
+		start, end  int
+		span, token string
+	}{
+		{"layout course-session", 0, 16, "CS 111 Fall 2019", ""},
+		{"[DeptCourse]", 0, 6, "CS 111", ""},
+		{"[Dept]", 0, 2, "CS", "CS"},
+		{"[Course]", 3, 6, "111", "111"},
+		{"Field Separator", 6, 7, " ", ""},
+		{"[Semester]", 7, 11, "Fall", "Fall"},
+		{"[Year]", 12, 16, "2019", "2019"},
+	}
+	for _, test := range tests {
+		path := findStep(root, test.name)
+		if path == nil {
+			t.Errorf("no %s step", test.name)
+			continue
+		}
+		step := path[len(path)-1]
+		if step.Start != test.start || step.End != test.end || step.Span != test.span || step.Token != test.token || !step.OK() {
+			t.Errorf("%s step %+v, want %d:%d %q => %q", test.name, step, test.start, test.end, test.span, test.token)
+		}
+	}
+
+	// The Parser itself does not record
+	if p.explain != nil {
+		t.Error("Explain left a recorder on the Parser")
+	}
+}
+
+// A failed step carries the most specific error, and so do the steps around it
+func TestExplainFailure(t *testing.T) {
+	root, _, err := testParser().Explain("CS 111 Fxll 2019")
+	if err == nil {
+		t.Fatal("Explain: no error")
+	}
+
+	path := findStep(root, "[Semester]")
+	if path == nil {
+		t.Fatal("no [Semester] step")
+	}
+	for _, step := range path {
+		if !strings.HasPrefix(step.Error, "975.15 - ") {
+			t.Errorf("%s step error %q, want 975.15", step.Name, step.Error)
+		}
+	}
+	semester := path[len(path)-1]
+	if semester.Start != 7 || semester.End != 11 || semester.Span != "Fxll" {
+		t.Errorf("[Semester] step %+v", semester)
+	}
+
+	var validated bool
+	for _, ev := range semester.Events {
+		validated = validated || ev.Funcid == "975.10" && ev.Attrs["token"] == "FXLL"
+	}
+	if !validated {
+		t.Errorf("[Semester] events %+v, want validateSemester()", semester.Events)
+	}
+
+	// every layout tried is a step of the root
+	var layouts []string
+	for _, step := range root.Steps {
+		if strings.HasPrefix(step.Name, "layout ") && !step.OK() {
+			layouts = append(layouts, step.Name)
+		}
+	}
+	if len(layouts) != len(DefaultLayouts()) {
+		t.Errorf("failed layout steps %v, want every layout", layouts)
+	}
+}
+
+func TestExplainJSON(t *testing.T) {
+	root, _, _ := testParser().Explain("CS 111 Fxll 2019")
+
+	data, err := json.Marshal(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Step
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Name != "Parse()" || decoded.Error != root.Error || len(decoded.Steps) != len(root.Steps) || len(decoded.Events) != len(root.Events) {
+		t.Errorf("decoded %s", data)
+	}
+}
@@ -0,0 +1,623 @@
+package courseparser
+
+import (
+	"log/slog"
+	"strconv"
+)
+
+//===========================================================
+//============ Input Layout Grammar =========================
+//===========================================================
+// The Fields of an input are described by a small declarative grammar that
+// the token extractors (getDeptToken, getCourseToken, getYearToken and
+// getSemesterToken) plug into. A Rule is a token, a separator, a sequence
+// or a set of alternatives, and carries the funcid codes of the errors it
+// reports, e.g. the default layout
+//
+//   [DeptCourse]   : [Dept] (nothing or one delimiter) [Course]
+//   Field Sep      : exactly one FieldSeparator
+//   [OfferSession] : [Term] ('-' [Term])  |  "AY" [Year]-[Year]
+//   [Term]         : [Year] (any delimiters) [Semester]  |  [Semester] (any delimiters) [Year]  |  [TermCode]
+//
+// with the session first and split session layouts listing the same Fields
+// in other orders (see DefaultLayouts).
+//
+// New input layouts are added as data (see Parser.Layouts), and Rule.Forms
+// lists every token order a layout accepts so it can be tested exhaustively.
+
+// TokenKind names the token extractor a leaf Rule runs
+type TokenKind int
+
+const (
+	NoToken TokenKind = iota
+	DeptToken
+	CourseToken
+	YearToken
+	SemesterToken
+	AcademicYearToken
+	TermCodeToken
+)
+
+// SepKind names the separator a separator Rule consumes
+type SepKind int
+
+const (
+	NoSep         SepKind = iota
+	OptDelimiter          // nothing or one delimiter
+	AnyDelimiters         // any number of delimiters
+	FieldSep              // one FieldSeparator, any run of separators with LenientSeparators
+	RangeSep              // '-' between the start and end term of a range, opening the range
+)
+
+type tokenDef struct {
+	name    string
+	starts  func(c rune) bool
+	get     func(p *Parser, inStr *ChStr, sel *CourseSelection) error
+	missing error
+	invalid error
+}
+
+var tokenTable = [...]tokenDef{
+	NoToken:       {name: "[]"},
+	DeptToken:     {"[Dept]", isLetter, (*Parser).getDeptToken, ErrMissingDept, ErrInvalidDept},
+	CourseToken:   {"[Course]", isNumber, (*Parser).getCourseToken, ErrMissingCourse, ErrInvalidCourse},
+	YearToken:     {"[Year]", isNumber, (*Parser).getYearToken, ErrMissingYear, ErrInvalidYear},
+	SemesterToken: {"[Semester]", isLetter, (*Parser).getSemesterToken, ErrMissingSemester, ErrInvalidSemester},
+
+	AcademicYearToken: {"[AcademicYear]", isLetter, (*Parser).getAcademicYearToken, ErrMissingSession, ErrInvalidRange},
+	TermCodeToken:     {"[TermCode]", isAlphaNumeric, (*Parser).getTermCodeToken, ErrMissingSession, ErrInvalidTermCode},
+}
+
+// String returns the token name, e.g. "[Year]"
+func (k TokenKind) String() string {
+	if k < 0 || int(k) >= len(tokenTable) {
+		return "[?" + strconv.Itoa(int(k)) + "]"
+	}
+	return tokenTable[k].name
+}
+
+// Rule is one node of a layout grammar
+type Rule struct {
+	Name string // for traces and messages, e.g. "[OfferSession]"
+
+	// Exactly one of Token, Sep, Seq or Alt
+	Token TokenKind
+	Sep   SepKind
+	Seq   []Rule // every rule, in order
+	Alt   []Rule // the first alternative that can start at the cursor and matches
+
+	Optional bool // skipped when it can not start at the cursor
+
+	// funcid codes carried by the table
+	Code    string // wraps the errors of the rule, with Msg
+	Msg     string
+	Missing string // the input ended before the rule
+	Expect  string // the rule can not start at the current character, else the token extractor reports it
+	Extra   string // (FieldSep) more than one separator
+
+	// Check runs once the rule matched, with the cursor after it. Its error
+	// is wrapped by CheckCode.
+	Check     func(p *Parser, inStr *ChStr, sel *CourseSelection) error
+	CheckCode string
+}
+
+// Layout is one accepted arrangement of the Fields of an input
+type Layout struct {
+	Name string // e.g. "course-session"
+	Rule Rule
+}
+
+// DeptCourseRules returns a new copy of the [DeptCourse] rules: [Dept],
+// nothing or one delimiter, [Course]
+func DeptCourseRules() []Rule {
+	return []Rule{
+		{Token: DeptToken, Code: "700.55", Msg: "During or after Parsing Dept ", Expect: "700.50"},
+		{Sep: OptDelimiter},
+		{Token: CourseToken, Code: "700.65", Msg: "After Parsing Course ", Missing: "700.58", Expect: "700.63"},
+	}
+}
+
+// TermRules returns a new copy of the [Term] rules: [Year] [Semester] or
+// [Semester] [Year], any number of delimiters between them, or an
+// institutional term code "201990" when the Parser has TermCodes
+func TermRules() []Rule {
+	return []Rule{
+		{Name: "[Year-Semester]", Seq: []Rule{
+			{Token: YearToken, Code: "800.25", Msg: "When Parsing Year Data "},
+			{Sep: AnyDelimiters, Code: "800.27", Msg: "Skipping Spaces before Semester "},
+			{Token: SemesterToken, Code: "800.29", Msg: "in getting Semester  ", Missing: "800.28"},
+		}},
+		{Name: "[Semester-Year]", Seq: []Rule{
+			{Token: SemesterToken, Code: "800.35", Msg: "After parsing Semester "},
+			{Sep: AnyDelimiters, Code: "800.37", Msg: "Skipping Spaces searching for Year "},
+			{Token: YearToken, Code: "800.39", Msg: "Getting Year Token ", Missing: "800.38"},
+		}},
+		{Token: TermCodeToken, Code: "800.45", Msg: "Getting the Term Code "},
+	}
+}
+
+// OfferSessionRules returns a new copy of the [OfferSession] rules: a
+// [Term], a range of terms "Fall 2019 - Spring 2020" or an academic year
+// "AY 2019-20" (see terms.go)
+func OfferSessionRules() []Rule {
+	return []Rule{
+		{Name: "[TermOrRange]", Seq: []Rule{
+			{Name: "[Term]", Alt: TermRules()},
+			{Name: "[TermRange]", Optional: true, Seq: []Rule{
+				{Sep: RangeSep},
+				{Name: "[Term]", Alt: TermRules(), Code: "800.60", Msg: "Getting the end of the term range ", Missing: "800.62", Expect: "800.64"},
+			}, Check: (*Parser).finishRange, CheckCode: "800.66"},
+		}},
+		{Token: AcademicYearToken, Code: "800.70", Msg: "Getting the Academic Year "},
+	}
+}
+
+// Layout names
+const (
+	CourseSessionLayout = "course-session" // "CS 111 Fall 2019"
+	SessionCourseLayout = "session-course" // "Fall 2019 CS111"
+	SplitSessionLayout  = "split-session"  // "2019 CS-111 Fall", the [OfferSession] around the [DeptCourse]
+)
+
+// DefaultLayouts returns the course first, session first and split session
+// layouts, in that order. Tokens are told apart by their type and by
+// validation, e.g. a leading alpha token is a [Semester] only when it is in
+// the Semester Lookup Dictionary, and a number is a [Year] only within the
+// year window. The Fields are always separated by one FieldSeparator.
+func DefaultLayouts() []Layout {
+	return []Layout{
+		{Name: CourseSessionLayout, Rule: Rule{Seq: []Rule{
+			{Name: "[DeptCourse]", Seq: DeptCourseRules(), Code: "1000.200", Msg: "in Parse() "},
+			{Sep: FieldSep, Missing: "1000.500", Expect: "1000.550", Extra: "1000.770"},
+			{Name: "[OfferSession]", Alt: OfferSessionRules(), Code: "1000.556", Msg: "Error while getting [OfferSession] Data", Missing: "1000.555", Expect: "1000.850"},
+		}}},
+
+		{Name: SessionCourseLayout, Rule: Rule{Seq: []Rule{
+			{Name: "[OfferSession]", Alt: OfferSessionRules(), Code: "1000.310", Msg: "Getting the leading [OfferSession] ", Expect: "1000.315"},
+			{Sep: FieldSep, Missing: "1000.320", Expect: "1000.325", Extra: "1000.330"},
+			{Name: "[DeptCourse]", Seq: DeptCourseRules(), Code: "1000.340", Msg: "Getting the [DeptCourse] after the [OfferSession] ", Missing: "1000.345"},
+		}}},
+
+		{Name: SplitSessionLayout, Rule: Rule{Alt: []Rule{
+			{Name: "[Year] [DeptCourse] [Semester]", Seq: []Rule{
+				{Token: YearToken, Code: "1000.410", Msg: "Getting the leading [Year] "},
+				{Sep: FieldSep, Missing: "1000.415", Expect: "1000.420", Extra: "1000.425"},
+				{Name: "[DeptCourse]", Seq: DeptCourseRules(), Code: "1000.430", Msg: "Getting the [DeptCourse] after the [Year] ", Missing: "1000.435"},
+				{Sep: FieldSep, Missing: "1000.440", Expect: "1000.445", Extra: "1000.450"},
+				{Token: SemesterToken, Code: "1000.460", Msg: "Getting the trailing [Semester] ", Missing: "1000.465"},
+			}},
+			{Name: "[Semester] [DeptCourse] [Year]", Seq: []Rule{
+				{Token: SemesterToken, Code: "1000.470", Msg: "Getting the leading [Semester] "},
+				{Sep: FieldSep, Missing: "1000.472", Expect: "1000.474", Extra: "1000.476"},
+				{Name: "[DeptCourse]", Seq: DeptCourseRules(), Code: "1000.480", Msg: "Getting the [DeptCourse] after the [Semester] ", Missing: "1000.482"},
+				{Sep: FieldSep, Missing: "1000.484", Expect: "1000.486", Extra: "1000.488"},
+				{Token: YearToken, Code: "1000.490", Msg: "Getting the trailing [Year] ", Missing: "1000.492"},
+			}},
+		}}},
+	}
+}
+
+// Forms lists every token order the rule accepts
+func (r Rule) Forms() [][]TokenKind {
+	var forms [][]TokenKind
+
+	switch {
+	case r.Token != NoToken:
+		forms = [][]TokenKind{{r.Token}}
+	case r.Sep != NoSep:
+		forms = [][]TokenKind{{}}
+	case r.Alt != nil:
+		for _, alt := range r.Alt {
+			forms = append(forms, alt.Forms()...)
+		}
+	default:
+		forms = [][]TokenKind{{}}
+		for _, sub := range r.Seq {
+			var next [][]TokenKind
+			for _, head := range forms {
+				for _, tail := range sub.Forms() {
+					next = append(next, append(append([]TokenKind(nil), head...), tail...))
+				}
+			}
+			forms = next
+		}
+	}
+	if r.Optional {
+		forms = append(forms, []TokenKind{})
+	}
+	return forms
+}
+
+// Name of the rule for messages
+func (r *Rule) name() string {
+	switch {
+	case r.Name != "":
+		return r.Name
+	case r.Token != NoToken:
+		return r.Token.String()
+	case r.Sep == FieldSep:
+		return "Field Separator"
+	case r.Sep == RangeSep:
+		return "Term Range Separator"
+	case r.Sep != NoSep:
+		return "Delimiter"
+	}
+	return "Layout"
+}
+
+// Reports whether the rule can start at the cursor
+func (r *Rule) starts(p *Parser, inStr *ChStr) bool {
+	if inStr.indx >= inStr.len {
+		return false
+	}
+
+	c := inStr.data[inStr.indx]
+	switch {
+	case r.Token == TermCodeToken:
+		return len(p.TermCodes) > 0 && tokenTable[r.Token].starts(c)
+	case r.Token != NoToken:
+		return tokenTable[r.Token].starts(c)
+	case r.Sep == FieldSep:
+		return p.isFieldSeparator(c) || p.LenientSeparators && p.isDelimiter(c)
+	case r.Sep == RangeSep:
+		return p.rangeSepLen(inStr) > 0
+	case r.Sep != NoSep:
+		return true
+	case r.Alt != nil:
+		for i := range r.Alt {
+			if r.Alt[i].starts(p, inStr) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for i := range r.Seq {
+		sub := &r.Seq[i]
+		switch {
+		case sub.Sep == OptDelimiter || sub.Sep == AnyDelimiters:
+			if p.isDelimiter(c) {
+				return true
+			}
+		case sub.Optional:
+			if sub.starts(p, inStr) {
+				return true
+			}
+		default:
+			return sub.starts(p, inStr)
+		}
+	}
+	return false
+}
+
+// First token the rule extracts
+func (r *Rule) firstToken() TokenKind {
+	switch {
+	case r.Token != NoToken:
+		return r.Token
+	case r.Alt != nil:
+		return r.Alt[0].firstToken()
+	}
+	for i := range r.Seq {
+		if k := r.Seq[i].firstToken(); k != NoToken {
+			return k
+		}
+	}
+	return NoToken
+}
+
+// Reports whether the rule extracts only [OfferSession] tokens
+func (r *Rule) sessionOnly() bool {
+	switch {
+	case r.Token != NoToken:
+		return r.Token == YearToken || r.Token == SemesterToken || r.Token == AcademicYearToken || r.Token == TermCodeToken
+	case r.Sep != NoSep:
+		return true
+	}
+	for _, sub := range append(append([]Rule(nil), r.Seq...), r.Alt...) {
+		if !sub.sessionOnly() {
+			return false
+		}
+	}
+	return true
+}
+
+// Failure kind when the input ends before the rule
+func (r *Rule) missingKind() error {
+	switch {
+	case r.Sep == FieldSep:
+		return ErrMissingFieldSeparator
+	case r.Token == NoToken && r.sessionOnly():
+		return ErrMissingSession
+	}
+	return tokenTable[r.firstToken()].missing
+}
+
+// Failure kind when the rule can not start at the current character
+func (r *Rule) invalidKind() error {
+	switch {
+	case r.Sep == FieldSep:
+		return ErrMissingFieldSeparator
+	case r.Sep == RangeSep:
+		return ErrInvalidRange
+	case r.Alt != nil:
+		return ErrInvalidCharacter
+	}
+	return tokenTable[r.firstToken()].invalid
+}
+
+// Returns code, or the interpreter's own code when the table has none
+func codeOr(code string, fallback string) string {
+	if code == "" {
+		return fallback
+	}
+	return code
+}
+
+//===========================================================
+//============ Layout Grammar Interpreter ===================
+//===========================================================
+
+// Runs rule at the cursor, extracting its tokens into the selection
+//
+//funcid:1100
+func (p *Parser) runRule(inStr *ChStr, sel *CourseSelection, rule *Rule) (err error) {
+
+	// Explain mode, anonymous groups (e.g. a Layout) are not steps of their own
+	if p.explain != nil && (rule.Name != "" || rule.Token != NoToken || rule.Sep != NoSep) {
+		p.enterStep(rule.name(), inStr.srcOffset(inStr.indx))
+		defer func() { p.leaveStep(inStr.srcOffset(inStr.indx), tokenValue(rule.Token, sel), err) }()
+	}
+
+	p.trace(slog.LevelDebug, "1100.10", "IN- : runRule()", inStr, "rule", rule.name())
+
+	start := inStr.indx
+	switch {
+	case rule.Sep != NoSep:
+		err = p.runSeparator(inStr, sel, rule)
+
+	case inStr.indx >= inStr.len:
+		return inStr.errorHere(codeOr(rule.Missing, "1100.20"), "Missing "+rule.name()+" Data ", rule.missingKind(), nil)
+
+	case (rule.Expect != "" || rule.Alt != nil) && !rule.starts(p, inStr):
+		return inStr.errorHere(codeOr(rule.Expect, "1100.30"), "Expecting "+rule.name()+" but finding '"+string(inStr.data[inStr.indx])+"'", rule.invalidKind(), nil)
+
+	case rule.Token != NoToken:
+		err = tokenTable[rule.Token].get(p, inStr, sel)
+
+	case rule.Alt != nil:
+		err = p.runAlt(inStr, sel, rule)
+
+	default:
+		retried := -1
+		for i := 0; i < len(rule.Seq); i++ {
+			sub := &rule.Seq[i]
+			if sub.Optional && !sub.starts(p, inStr) {
+				continue
+			}
+
+			// Recovering, see recover.go. Once the input is exhausted each
+			// missing token is a Diagnostic, the separators are not
+			if p.Recover && sub.Sep != NoSep && inStr.indx >= inStr.len {
+				continue
+			}
+			at := inStr.indx
+			if err = p.runRule(inStr, sel, sub); err != nil {
+				if !p.Recover {
+					break
+				}
+				p.recoverFrom(inStr, sel, sub, err)
+				err = nil
+
+				// A token behind extra separators is tried once more after them
+				if sub.Sep == NoSep && inStr.indx == at && i != retried && p.skipSeparators(inStr) {
+					retried, i = i, i-1
+				}
+			}
+		}
+	}
+
+	// Once recovering from a failure, the tokens are not all valid to check
+	if err == nil && rule.Check != nil && len(inStr.diags) == 0 {
+		if err = rule.Check(p, inStr, sel); err != nil {
+			err = inStr.errorAt(codeOr(rule.CheckCode, "1100.80"), start, "After "+rule.name()+" ", nil, err)
+		}
+	}
+
+	if err != nil {
+		if rule.Code != "" {
+			err = inStr.errorHere(rule.Code, rule.Msg, nil, err)
+		}
+		return err
+	}
+
+	p.trace(slog.LevelDebug, "1100.90", "OUT : runRule()", inStr, "rule", rule.name(), "tokens", sel.Tokens())
+
+	return nil
+}
+
+// Tries the alternatives that can start at the cursor in order. When none
+// matches, the failure of the one that got furthest along the input is
+// reported (the earliest one on a tie). Recovering, an alternative that
+// matched with Diagnostics only wins when no other matches cleanly, and
+// then the one with the fewest Diagnostics does.
+//
+//funcid:1150
+func (p *Parser) runAlt(inStr *ChStr, sel *CourseSelection, rule *Rule) error {
+	var bestErr error
+	var bestStr, recStr ChStr
+	var bestSel, recSel CourseSelection
+	var recovered bool
+
+	for i := range rule.Alt {
+		alt := &rule.Alt[i]
+		if !alt.starts(p, inStr) {
+			continue
+		}
+
+		tryStr, trySel := *inStr, *sel
+		trySel.Corrections = append([]Correction(nil), sel.Corrections...)
+		err := p.runRule(&tryStr, &trySel, alt)
+		if err == nil && len(tryStr.diags) == len(inStr.diags) {
+			*inStr, *sel = tryStr, trySel
+			return nil
+		}
+		if err == nil {
+			p.trace(slog.LevelDebug, "1150.40", "MID : runAlt() alternative matched with diagnostics", inStr, "alt", alt.name(), "diagnostics", len(tryStr.diags)-len(inStr.diags))
+			if !recovered || len(tryStr.diags) < len(recStr.diags) {
+				recovered, recStr, recSel = true, tryStr, trySel
+			}
+			continue
+		}
+
+		p.trace(slog.LevelDebug, "1150.50", "MID : runAlt() alternative did not match", inStr, "alt", alt.name())
+		if bestErr == nil || failOffset(err) > failOffset(bestErr) {
+			bestErr, bestStr, bestSel = err, tryStr, trySel
+		}
+	}
+
+	if recovered {
+		*inStr, *sel = recStr, recSel
+		return nil
+	}
+	*inStr, *sel = bestStr, bestSel
+	return bestErr
+}
+
+// Consumes the separator of a separator rule
+//
+//funcid:1170
+func (p *Parser) runSeparator(inStr *ChStr, sel *CourseSelection, rule *Rule) error {
+	if inStr.indx >= inStr.len {
+		if rule.Sep == FieldSep {
+			return inStr.errorHere(codeOr(rule.Missing, "1170.20"), "Missing Field Separator and Session Data ", ErrMissingFieldSeparator, nil)
+		}
+		return nil
+	}
+
+	c := inStr.data[inStr.indx]
+	switch rule.Sep {
+	case OptDelimiter:
+		if p.isDelimiter(c) {
+			inStr.indx++
+		}
+
+	case AnyDelimiters:
+		return p.skipSpacesDelims(inStr)
+
+	case FieldSep:
+		if !rule.starts(p, inStr) {
+			return inStr.errorHere(codeOr(rule.Expect, "1170.30"), "Missing Field Separator.  Expecting "+strconv.QuoteRune(p.FieldSeparator)+" but finding "+strconv.QuoteRune(c), ErrMissingFieldSeparator, nil).with("expected", strconv.QuoteRune(p.FieldSeparator))
+		}
+
+		// Skipping the Field Separator, or in lenient mode every separator
+		inStr.indx++
+		for p.LenientSeparators && rule.starts(p, inStr) {
+			inStr.indx++
+		}
+
+		if inStr.indx < inStr.len && (p.isDelimiter(inStr.data[inStr.indx]) || p.isFieldSeparator(inStr.data[inStr.indx])) {
+			return inStr.errorHere(codeOr(rule.Extra, "1170.40"), "Only One Delimiter allowed  between Fields (see Parser.LenientSeparators). Found Char ==>'"+string(inStr.data[inStr.indx])+"'", ErrExtraDelimiter, nil)
+		}
+
+	case RangeSep:
+		n := p.rangeSepLen(inStr)
+		if n == 0 {
+			return inStr.errorHere(codeOr(rule.Expect, "1170.50"), "Expecting '-' and the end of the term range but finding "+strconv.QuoteRune(c), ErrInvalidRange, nil)
+		}
+		inStr.indx += n
+		p.openRange(inStr, sel)
+	}
+	return nil
+}
+
+// Matches the input against the Parser's Layouts in order, the first that
+// matches the whole input wins and is recorded in sel.Layout. When none
+// does, the failure of the layout that got furthest along the input is
+// reported (the earliest layout on a tie). Recovering, the layout with the
+// fewest Diagnostics wins.
+//
+//funcid:1050
+func (p *Parser) matchLayout(inStr *ChStr, sel *CourseSelection) error {
+	var bestErr error
+	var bestStr, recStr ChStr
+	var bestSel, recSel CourseSelection
+	var recovered bool
+
+	start := inStr.indx
+	for i := range p.Layouts {
+		layout := &p.Layouts[i]
+		tryStr, trySel := *inStr, *sel
+		trySel.Corrections = append([]Correction(nil), sel.Corrections...)
+
+		p.enterStep("layout "+layout.Name, tryStr.srcOffset(tryStr.indx))
+		err := p.runRule(&tryStr, &trySel, &layout.Rule)
+
+		// Nothing but delimiters may follow the last Field
+		if err == nil && tryStr.indx < tryStr.len {
+			end := tryStr.indx
+			err = p.skipSpacesDelims(&tryStr)
+			if err != nil || tryStr.indx < tryStr.len {
+				err = tryStr.errorHere("1000.600", "Unexpected data after the last Field (one selection per entry, see ParseList) '"+tryStr.rest(end)+"'", ErrTrailingData, err)
+			}
+			if err != nil && p.Recover {
+				tryStr.addDiagnostic(Diagnostic{Err: err})
+				tryStr.indx, err = tryStr.len, nil
+			}
+		}
+
+		p.leaveStep(tryStr.srcOffset(tryStr.indx), "", err)
+
+		if err == nil {
+			trySel.Layout = layout.Name
+			if len(tryStr.diags) == len(inStr.diags) {
+				*inStr, *sel = tryStr, trySel
+				return p.checkLayout(inStr, sel, start)
+			}
+			p.trace(slog.LevelDebug, "1050.40", "MID : matchLayout() layout matched with diagnostics", &tryStr, "layout", layout.Name, "diagnostics", len(tryStr.diags)-len(inStr.diags))
+			if !recovered || len(tryStr.diags) < len(recStr.diags) {
+				recovered, recStr, recSel = true, tryStr, trySel
+			}
+			continue
+		}
+
+		p.trace(slog.LevelDebug, "1050.50", "MID : matchLayout() layout did not match", &tryStr, "layout", layout.Name)
+		if bestErr == nil || failOffset(err) > failOffset(bestErr) {
+			bestErr, bestStr, bestSel = err, tryStr, trySel
+		}
+	}
+
+	if recovered {
+		*inStr, *sel = recStr, recSel
+		return nil
+	}
+	if bestErr == nil {
+		return inStr.panicHere("1050.20", "No input Layouts")
+	}
+	*inStr, *sel = bestStr, bestSel
+	return bestErr
+}
+
+// Checks the selection of the layout that matched from start against the
+// Catalog, whatever the layout. Recovering, a failure is a Diagnostic.
+func (p *Parser) checkLayout(inStr *ChStr, sel *CourseSelection, start int) error {
+	err := p.checkOffering(sel)
+	if err == nil {
+		return nil
+	}
+
+	err = inStr.errorAt("1050.60", start, "After the "+sel.Layout+" layout ", nil, err)
+	if p.Recover {
+		inStr.addDiagnostic(Diagnostic{Err: err})
+		return nil
+	}
+	return err
+}
+
+// Input position of a failure, -1 if unknown
+func failOffset(err error) int {
+	if pe := Locate(err); pe != nil {
+		return pe.Offset
+	}
+	return -1
+}
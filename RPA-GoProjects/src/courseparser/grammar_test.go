@@ -0,0 +1,244 @@
+package courseparser
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// An input built from a layout rule, and the token order it takes
+type sample struct {
+	form  []TokenKind
+	input string
+	terms int // terms written so far, the next [Year] and [Semester] are of term "terms"
+	open  int // tokens of the current [Year]/[Semester] term written so far
+}
+
+// Token values of the first and second term of a sample, so a range is "Fall 2019 - Spring 2020"
+var sampleTerms = [2]struct{ year, semester, code string }{
+	{"2019", "Fall", "201990"},
+	{"2020", "Spring", "202020"},
+}
+
+// Separators written for each separator kind, every one of them accepted
+var sampleSeps = map[SepKind][]string{
+	OptDelimiter:  {"", " ", "-", ":"},
+	AnyDelimiters: {"", " ", " - "},
+	FieldSep:      {" "},
+	RangeSep:      {" - ", "-"},
+}
+
+// Builds every input the rule accepts from the sample token values, one per
+// form and separator
+func samples(r Rule, from sample) []sample {
+	var out []sample
+
+	switch {
+	case r.Token != NoToken:
+		s := from
+		s.form = append(append([]TokenKind(nil), from.form...), r.Token)
+		switch r.Token {
+		case DeptToken:
+			s.input += "CS"
+		case CourseToken:
+			s.input += "111"
+		case YearToken, SemesterToken:
+			if r.Token == YearToken {
+				s.input += sampleTerms[s.terms].year
+			} else {
+				s.input += sampleTerms[s.terms].semester
+			}
+			if s.open++; s.open == 2 {
+				s.terms, s.open = s.terms+1, 0
+			}
+		case TermCodeToken:
+			s.input += sampleTerms[s.terms].code
+			s.terms++
+		case AcademicYearToken:
+			s.input += "AY 2019-20"
+		}
+		out = []sample{s}
+	case r.Sep != NoSep:
+		for _, sep := range sampleSeps[r.Sep] {
+			s := from
+			s.input += sep
+			out = append(out, s)
+		}
+	case r.Alt != nil:
+		for _, alt := range r.Alt {
+			out = append(out, samples(alt, from)...)
+		}
+	default:
+		out = []sample{from}
+		for _, sub := range r.Seq {
+			var next []sample
+			for _, s := range out {
+				next = append(next, samples(sub, s)...)
+			}
+			out = next
+		}
+	}
+	if r.Optional {
+		out = append(out, from)
+	}
+	return out
+}
+
+func formString(form []TokenKind) string {
+	return fmt.Sprint(form)
+}
+
+// Every form of every default layout parses, in that layout, whatever
+// separators it is written with
+func TestLayoutForms(t *testing.T) {
+	p := testParser()
+	banner, _ := NewTermCodec("banner")
+	p.TermCodes = []TermCodec{banner}
+
+	for _, layout := range DefaultLayouts() {
+		var forms, built []string
+		for _, form := range layout.Rule.Forms() {
+			forms = append(forms, formString(form))
+		}
+
+		seen := make(map[string]bool)
+		for _, s := range samples(layout.Rule, sample{}) {
+			if f := formString(s.form); !seen[f] {
+				seen[f] = true
+				built = append(built, f)
+			}
+
+			sel, err := p.Parse(s.input)
+			if err != nil {
+				t.Errorf("%s %v: %q: %v", layout.Name, s.form, s.input, err)
+				continue
+			}
+			if sel.Layout != layout.Name || sel.Dept != "CS" || sel.Course != "111" {
+				t.Errorf("%s %v: %q: layout %q, tokens %v", layout.Name, s.form, s.input, sel.Layout, sel.Tokens())
+			}
+
+			var want string
+			switch {
+			case s.form[len(s.form)-1] == AcademicYearToken || s.form[0] == AcademicYearToken:
+				want = "Fall 2019 - Summer 2020"
+			case s.terms == 2:
+				want = "Fall 2019 - Spring 2020"
+			default:
+				want = "Fall 2019"
+			}
+			if got := sel.Term().String(); sel.Range != nil {
+				got = sel.Range.Start.String() + " - " + sel.Range.End.String()
+				if got != want {
+					t.Errorf("%s %v: %q: range %s, want %s", layout.Name, s.form, s.input, got, want)
+				}
+			} else if got != want {
+				t.Errorf("%s %v: %q: term %s, want %s", layout.Name, s.form, s.input, got, want)
+			}
+		}
+
+		sort.Strings(forms)
+		sort.Strings(built)
+		if !reflect.DeepEqual(forms, built) {
+			t.Errorf("%s: Forms() = %v, the rule builds %v", layout.Name, forms, built)
+		}
+	}
+}
+
+func TestForms(t *testing.T) {
+	term := Rule{Alt: TermRules()}
+	want := [][]TokenKind{{YearToken, SemesterToken}, {SemesterToken, YearToken}, {TermCodeToken}}
+	if got := term.Forms(); !reflect.DeepEqual(got, want) {
+		t.Errorf("[Term] Forms() = %v, want %v", got, want)
+	}
+
+	dept := Rule{Seq: DeptCourseRules(), Optional: true}
+	want = [][]TokenKind{{DeptToken, CourseToken}, {}}
+	if got := dept.Forms(); !reflect.DeepEqual(got, want) {
+		t.Errorf("optional [DeptCourse] Forms() = %v, want %v", got, want)
+	}
+}
+
+// A leading alpha token is a [Semester] only when it is in the dictionary, so
+// auto-correct never turns a [Dept] into one
+func TestLeadingSemesterNotCorrected(t *testing.T) {
+	p := testParser()
+	p.AutoCorrect = 1
+
+	for _, input := range []string{"CS 2020 MATH 101", "Spirng 2020 MATH 101", "Spirng MATH 101 2020"} {
+		if sel, err := p.Parse(input); err == nil {
+			t.Errorf("%q: parsed %v %+v, want an error", input, sel.Tokens(), sel.Corrections)
+		}
+	}
+
+	for _, input := range []string{"CS 111 Spirng 2020", "2020 MATH 101 Spirng", "CS 111 Fall 2019 - Spirng 2020"} {
+		sel, err := p.Parse(input)
+		if err != nil || len(sel.Corrections) != 1 || sel.Corrections[0].To != "Spring" {
+			t.Errorf("%q: %v %+v %v, want Spirng corrected to Spring", input, sel.Tokens(), sel.Corrections, err)
+		}
+	}
+
+	results := p.ParseList("CS 2020, MATH 101 Fall 2020")
+	if len(results) != 2 || results[0].Err != nil || results[0].Selection.Dept != "CS" || results[0].Selection.Course != "2020" {
+		t.Errorf("ParseList: %+v, want CS 2020 sharing Fall 2020", results)
+	}
+}
+
+// The rules are copies, changing one does not change the default layouts
+func TestRulesCopies(t *testing.T) {
+	rules := DeptCourseRules()
+	rules[0].Code = "0.0"
+	if DeptCourseRules()[0].Code == "0.0" {
+		t.Error("DeptCourseRules() shares its rules")
+	}
+
+	rules = OfferSessionRules()
+	rules[0].Seq[0].Alt[0].Name = "changed"
+	if OfferSessionRules()[0].Seq[0].Alt[0].Name == "changed" || TermRules()[0].Name == "changed" {
+		t.Error("OfferSessionRules() shares its rules")
+	}
+}
+
+// The Catalog checks the selection of any layout, a custom one too
+func TestLayoutOffering(t *testing.T) {
+	custom := Layout{Name: "semester-year-course", Rule: Rule{Seq: []Rule{
+		{Token: SemesterToken},
+		{Sep: FieldSep},
+		{Token: YearToken},
+		{Sep: FieldSep},
+		{Name: "[DeptCourse]", Seq: DeptCourseRules()},
+	}}}
+
+	tests := []struct {
+		input  string
+		custom bool
+		layout string
+		kind   error
+	}{
+		{"CS 111 Fall 2019", false, CourseSessionLayout, nil},
+		{"CS 111 Spring 2019", false, CourseSessionLayout, ErrCourseNotOffered},
+		{"Spring 2019 CS 111", false, SessionCourseLayout, ErrCourseNotOffered},
+		{"2019 CS 111 Spring", false, SplitSessionLayout, ErrCourseNotOffered},
+		{"Fall 2019 - Spring 2020 CS 111", false, SessionCourseLayout, nil},
+		{"Spring 2019 - Summer 2019 CS 111", false, SessionCourseLayout, ErrCourseNotOffered},
+		{"Fall 2019 CS 111", true, custom.Name, nil},
+		{"Spring 2019 CS 111", true, custom.Name, ErrCourseNotOffered},
+	}
+	for _, test := range tests {
+		p := testParser()
+		p.Catalog = NewCatalog()
+		p.Catalog.AddCourse("CS", "111", Offering{Semester: "Fall"})
+		if test.custom {
+			p.Layouts = []Layout{custom}
+		}
+
+		sel, err := p.Parse(test.input)
+		if !errors.Is(err, test.kind) || sel.Layout != test.layout {
+			t.Errorf("%q: layout %q error %v, want %q %v", test.input, sel.Layout, err, test.layout, test.kind)
+		}
+		if pe := Locate(err); test.kind != nil && (pe == nil || pe.Code != "1050.60" || pe.Offset != 0) {
+			t.Errorf("%q: error at %+v, want 1050.60 at 0", test.input, pe)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package courseparser
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+//===========================================================
+//============ Multiple Course Selections per Line ==========
+//===========================================================
+// "CS111 Fall 2019, MATH 220 Spring 2020; PHYS-101 F2020" holds three
+// entries. Each entry is parsed on its own, so one bad entry does not fail
+// the whole line.
+//
+// Shared-session shorthand : an entry holding only a [DeptCourse] Field takes
+// the [OfferSession] of the next entry that has one, and an entry holding
+// only an [OfferSession] Field repeats the course(s) of the entry before it
+//   "CS 111, CS 112, MATH 220 Fall 2019" => CS 111, CS 112, MATH 220 all Fall 2019
+//   "CS 111 Fall 2019, Spring 2020"      => CS 111 Fall 2019, CS 111 Spring 2020
+// A session only entry with no course before it is an error, as is a course
+// only entry with no session after it. An entry that fails to parse breaks
+// the shorthand, "CS 111, CS#112, Fall 2019" fails all three entries.
+
+// Default Parser.EntrySeparators
+const DefaultEntrySeparators = ",;"
+
+// Result is one entry of a multi selection input line
+type Result struct {
+	Input     string // entry text, without its separator and surrounding spaces
+	Offset    int    // rune offset of the entry in the line
+	Selection CourseSelection
+	Err       error // error positions are offsets into the whole line
+}
+
+// ParseList parses a line of selections with the default Parser configuration. See Parser.ParseList.
+func ParseList(input string) []Result {
+	return defaultParser.ParseList(input)
+}
+
+// ParseList splits input on the Parser's EntrySeparators and parses every
+// non blank entry independently, expanding the shared-session shorthand.
+// It returns one Result per selection in input order; a session only entry
+// gives one Result per course it is shared with.
+//
+//funcid:1200
+func (p *Parser) ParseList(input string) []Result {
+	var results []Result
+	var pending []int           // results holding a [DeptCourse] only entry, waiting for a session
+	var group []CourseSelection // courses of the last entry (group) that had a session
+
+	p = p.withDefaults()
+	for _, entry := range p.splitEntries(input) {
+		sel, err := p.Parse(entry.Input)
+
+		if err == nil {
+			// A full entry, which also completes any pending courses
+			group = p.shareSession(results, pending, sel)
+			group = append(group, sel)
+			pending = nil
+			entry.Selection = sel
+			results = append(results, entry)
+			continue
+		}
+
+		// A session only entry, for the pending courses or else the last group.
+		// It is tried first, so "Fall 2019" is never read as Dept "FALL" Course "2019".
+		if session, errSession := p.parseSessionOnly(entry.Input); errSession == nil {
+			switch {
+			case len(pending) > 0:
+				group = p.shareSession(results, pending, session)
+				pending = nil
+			case len(group) > 0:
+				for _, course := range group {
+					shared := entry
+					shared.Selection, shared.Err = p.withSession(course, session, entry.Offset)
+					results = append(results, shared)
+				}
+			default:
+				pe := &ParseError{Code: "1200.20", Offset: entry.Offset, Msg: "No [DeptCourse] precedes the [OfferSession] entry '" + entry.Input + "'", Kind: ErrMissingCourse}
+				entry.Selection, entry.Err = session, pe.with("entry", entry.Input)
+				results = append(results, entry)
+			}
+			continue
+		}
+
+		// A course only entry, waiting for the next session
+		if course, errCourse := p.parseDeptCourseOnly(entry.Input); errCourse == nil {
+			entry.Selection = course
+			results = append(results, entry)
+			pending = append(pending, len(results)-1)
+			continue
+		}
+
+		// A failed entry ends the shorthand: neither the pending courses nor
+		// the last group reach past it
+		noSession(results, pending)
+		pending, group = nil, nil
+		shiftOffsets(err, entry.Offset)
+		entry.Selection, entry.Err = sel, err
+		results = append(results, entry)
+	}
+	noSession(results, pending)
+
+	for _, r := range results {
+		setColumns(r.Err, input)
+	}
+	return results
+}
+
+// Gives the pending course results the session of sel, returning their courses
+func (p *Parser) shareSession(results []Result, pending []int, sel CourseSelection) []CourseSelection {
+	var courses []CourseSelection
+
+	for _, i := range pending {
+		courses = append(courses, results[i].Selection)
+		results[i].Selection, results[i].Err = p.withSession(results[i].Selection, sel, results[i].Offset)
+	}
+	return courses
+}
+
+// Fails the pending course results, no session followed them
+func noSession(results []Result, pending []int) {
+	for _, i := range pending {
+		end := results[i].Offset + utf8.RuneCountInString(results[i].Input)
+		pe := &ParseError{Code: "1200.30", Offset: end, Msg: "No [OfferSession] follows the [DeptCourse] entry '" + results[i].Input + "'", Kind: ErrMissingSession}
+		results[i].Err = pe.with("entry", results[i].Input)
+	}
+}
+
+// Combines the [DeptCourse] tokens of course with the [OfferSession] tokens
+// of session, checking the course is offered in it
+func (p *Parser) withSession(course, session CourseSelection, offset int) (CourseSelection, error) {
+	sel := course
+	sel.Semester, sel.Year, sel.Range, sel.Locale = session.Semester, session.Year, session.Range, session.Locale
+	p.encodeTerms(&sel)
+	sel.Corrections = append(append([]Correction(nil), course.Corrections...), session.Corrections...)
+
+	if p.Catalog != nil {
+		if err := p.validateOffering(&sel); err != nil {
+			return sel, &ParseError{Code: "1200.40", Offset: offset, Msg: "Shared [OfferSession] ", Err: err}
+		}
+	}
+	return sel, nil
+}
+
+// Parses an entry holding only a [DeptCourse] Field
+//
+//funcid:1250
+func (p *Parser) parseDeptCourseOnly(input string) (CourseSelection, error) {
+	var sel CourseSelection
+
+	inStr := newChStr(input)
+	if err := p.skipSpacesDelims(&inStr); err != nil {
+		return sel, err
+	}
+	if err := p.getDeptCourse(&inStr, &sel); err != nil {
+		return sel, err
+	}
+	return sel, p.expectEnd(&inStr, "1250.30")
+}
+
+// Parses an entry holding only an [OfferSession] Field
+//
+//funcid:1270
+func (p *Parser) parseSessionOnly(input string) (CourseSelection, error) {
+	var sel CourseSelection
+
+	inStr := newChStr(input)
+	if err := p.skipSpacesDelims(&inStr); err != nil {
+		return sel, err
+	}
+	if err := p.getOfferSession(&inStr, &sel); err != nil {
+		return sel, err
+	}
+	return sel, p.expectEnd(&inStr, "1270.30")
+}
+
+// Only delimiters may follow the last Field of an entry. A Field
+// recovered from (see Parser.Recover) did not parse either.
+func (p *Parser) expectEnd(inStr *ChStr, code string) error {
+	if len(inStr.diags) > 0 {
+		return diagnosticsError(inStr.diags)
+	}
+	if inStr.indx >= inStr.len {
+		return nil
+	}
+	err := p.skipSpacesDelims(inStr)
+	if err != nil || inStr.indx < inStr.len {
+		return inStr.errorHere(code, "Unexpected data after the last Field '"+inStr.rest(inStr.indx)+"'", ErrTrailingData, err)
+	}
+	return nil
+}
+
+// Splits input into its non blank entries. Full-width separators ("，")
+// match their ASCII EntrySeparators.
+func (p *Parser) splitEntries(input string) []Result {
+	var entries []Result
+
+	runes := []rune(input)
+	start := 0
+	for i := 0; i <= len(runes); i++ {
+		if i < len(runes) && !strings.ContainsRune(p.EntrySeparators, foldRune(runes[i])) {
+			continue
+		}
+		text := string(runes[start:i])
+		entry := strings.TrimSpace(text)
+		if entry != "" {
+			lead := utf8.RuneCountInString(text[:strings.Index(text, entry)])
+			entries = append(entries, Result{Input: entry, Offset: start + lead})
+		}
+		start = i + 1
+	}
+	return entries
+}
+
+// Moves the positions of an entry's error stack (and Diagnostics) from the entry to the line
+func shiftOffsets(err error, offset int) {
+	walkErrors(err, func(pe *ParseError) {
+		if pe.Offset >= 0 {
+			pe.Offset += offset
+		}
+	})
+}
+
+// Sets the columns of an error stack's positions along the line
+func setColumns(err error, line string) {
+	walkErrors(err, func(pe *ParseError) {
+		if pe.Offset >= 0 {
+			pe.Column = Column(line, pe.Offset)
+		}
+	})
+}
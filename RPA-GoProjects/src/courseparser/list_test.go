@@ -0,0 +1,144 @@
+package courseparser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseList(t *testing.T) {
+	type want struct {
+		input  string
+		offset int
+		tokens string
+	}
+	tests := []struct {
+		line    string
+		results []want
+	}{
+		{"CS111 Fall 2019, MATH 220 Spring 2020; PHYS-101 F2020", []want{
+			{"CS111 Fall 2019", 0, "CS 111 2019 Fall"},
+			{"MATH 220 Spring 2020", 17, "MATH 220 2020 Spring"},
+			{"PHYS-101 F2020", 39, "PHYS 101 2020 Fall"},
+		}},
+		// the courses before a session take it
+		{"CS 111, CS 112, MATH 220 Fall 2019", []want{
+			{"CS 111", 0, "CS 111 2019 Fall"},
+			{"CS 112", 8, "CS 112 2019 Fall"},
+			{"MATH 220 Fall 2019", 16, "MATH 220 2019 Fall"},
+		}},
+		// a session after the courses repeats them
+		{"CS 111 Fall 2019, Spring 2020", []want{
+			{"CS 111 Fall 2019", 0, "CS 111 2019 Fall"},
+			{"Spring 2020", 18, "CS 111 2020 Spring"},
+		}},
+		{"CS 111, MATH 220 Fall 2019, Spring 2020", []want{
+			{"CS 111", 0, "CS 111 2019 Fall"},
+			{"MATH 220 Fall 2019", 8, "MATH 220 2019 Fall"},
+			{"Spring 2020", 28, "CS 111 2020 Spring"},
+			{"Spring 2020", 28, "MATH 220 2020 Spring"},
+		}},
+		{"CS 111, F 2019", []want{
+			{"CS 111", 0, "CS 111 2019 Fall"},
+		}},
+		{"  ,CS 111 Fall 2019 ;; ", []want{
+			{"CS 111 Fall 2019", 3, "CS 111 2019 Fall"},
+		}},
+	}
+
+	p := testParser()
+	for _, test := range tests {
+		results := p.ParseList(test.line)
+		if len(results) != len(test.results) {
+			t.Errorf("%q: %d results, want %d", test.line, len(results), len(test.results))
+			continue
+		}
+		for i, r := range results {
+			w := test.results[i]
+			if r.Err != nil {
+				t.Errorf("%q: result %d: %v", test.line, i, r.Err)
+				continue
+			}
+			if got := joinTokens(r.Selection.Tokens()); r.Input != w.input || r.Offset != w.offset || got != w.tokens {
+				t.Errorf("%q: result %d = %q @%d %q, want %q @%d %q", test.line, i, r.Input, r.Offset, got, w.input, w.offset, w.tokens)
+			}
+		}
+	}
+}
+
+func TestParseListErrors(t *testing.T) {
+	type want struct {
+		input  string
+		kind   error
+		code   string
+		offset int
+		column int
+	}
+	tests := []struct {
+		line    string
+		results []want
+	}{
+		// a leading session is not read as Dept "FALL" Course "2019"
+		{"Fall 2019, CS 111, MATH 220", []want{
+			{"Fall 2019", ErrMissingCourse, "1200.20", 0, 0},
+			{"CS 111", ErrMissingSession, "1200.30", 17, 17},
+			{"MATH 220", ErrMissingSession, "1200.30", 27, 27},
+		}},
+		{"CS 111, MATH 220", []want{
+			{"CS 111", ErrMissingSession, "1200.30", 6, 6},
+			{"MATH 220", ErrMissingSession, "1200.30", 16, 16},
+		}},
+		// a failed entry ends the shorthand, the courses before it get no session
+		{"CS 111, CS#112, Fall 2019", []want{
+			{"CS 111", ErrMissingSession, "1200.30", 6, 6},
+			{"CS#112", ErrInvalidCharacter, "600.40", 10, 10},
+			{"Fall 2019", ErrMissingCourse, "1200.20", 16, 16},
+		}},
+		// and a session after it repeats no course
+		{"CS 111 Fxll 2019, Spring 2020", []want{
+			{"CS 111 Fxll 2019", ErrInvalidSemester, "950.35", 7, 7},
+			{"Spring 2020", ErrMissingCourse, "1200.20", 18, 18},
+		}},
+		{"CS 111 Fall 2019, CS#112 Fall 2019, Spring 2020", []want{
+			{"CS 111 Fall 2019", nil, "", 0, 0},
+			{"CS#112 Fall 2019", ErrInvalidCharacter, "600.40", 20, 20},
+			{"Spring 2020", ErrMissingCourse, "1200.20", 36, 36},
+		}},
+		// error positions are offsets into the line, columns count wide characters twice
+		{"CS 111 Fall 2019, MATH 220 Fxll 2019", []want{
+			{"CS 111 Fall 2019", nil, "", 0, 0},
+			{"MATH 220 Fxll 2019", ErrInvalidSemester, "950.35", 27, 27},
+		}},
+		{"ＣＳ １１１ Ｆａｌｌ ２０１９，MATH 220 Fxll 2019", []want{
+			{"ＣＳ １１１ Ｆａｌｌ ２０１９", nil, "", 0, 0},
+			{"MATH 220 Fxll 2019", ErrInvalidSemester, "950.35", 26, 40},
+		}},
+	}
+
+	p := testParser()
+	for _, test := range tests {
+		results := p.ParseList(test.line)
+		if len(results) != len(test.results) {
+			t.Errorf("%q: %d results, want %d", test.line, len(results), len(test.results))
+			continue
+		}
+		for i, r := range results {
+			w := test.results[i]
+			if r.Input != w.input {
+				t.Errorf("%q: result %d input %q, want %q", test.line, i, r.Input, w.input)
+			}
+			if w.kind == nil {
+				if r.Err != nil {
+					t.Errorf("%q: result %d: %v", test.line, i, r.Err)
+				}
+				continue
+			}
+			if !errors.Is(r.Err, w.kind) {
+				t.Errorf("%q: result %d error %v, want %v", test.line, i, r.Err, w.kind)
+				continue
+			}
+			if pe := Locate(r.Err); pe == nil || pe.Code != w.code || pe.Offset != w.offset || pe.Column != w.column {
+				t.Errorf("%q: result %d error %+v, want code %s offset %d column %d", test.line, i, pe, w.code, w.offset, w.column)
+			}
+		}
+	}
+}
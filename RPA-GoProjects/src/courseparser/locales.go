@@ -0,0 +1,148 @@
+package courseparser
+
+import (
+	"fmt"
+	"strings"
+)
+
+//===========================================================
+//============ Multilingual Semester Names ==================
+//===========================================================
+// An international campus types the [Semester] in its own language:
+// "Otoño 2019", "Primavera 2020", "Herbst 2019", "Été 2020". A locale
+// dictionary maps those names onto the canonical terms of the Semester
+// Lookup Dictionary, so the parsed Semester is always e.g. "Fall".
+// Parser.Locale selects the locale of a Parser (see WithLocale for one
+// parse), or AutoLocale tries every locale in SemesterLocales order and
+// reports the one the name was found in as CourseSelection.Locale. The
+// Parser's own Semesters always win.
+//
+// The names are looked up in the normalized input (see unicode.go), so a
+// decomposed "E" + U+0301 matches "Été"; each accented name also has its
+// plain spelling as an alias ("Ete", "Otono", "Fruehling").
+
+// Locales with builtin semester names, see Parser.Locale
+var SemesterLocales = []string{"es", "de", "fr"}
+
+// AutoLocale as the Parser.Locale detects the locale of the [Semester]
+const AutoLocale = "auto"
+
+// Builders of the locale dictionaries, aliases by canonical term
+var localeBuilders = map[string]func() *SemesterDict{
+	"es": func() *SemesterDict {
+		d := NewSemesterDict()
+		d.mustAdd("Winter", "INVIERNO", "INV")
+		d.mustAdd("Spring", "PRIMAVERA", "PRIM")
+		d.mustAdd("Summer", "VERANO", "VER")
+		d.mustAdd("Fall", "OTOÑO", "OTONO", "OTO")
+		return d
+	},
+	"de": func() *SemesterDict {
+		d := NewSemesterDict()
+		d.mustAdd("Winter", "WINTERSEMESTER", "WISE", "WS")
+		d.mustAdd("Spring", "FRÜHLING", "FRUEHLING", "FRUHLING", "FRÜHJAHR", "FRUEHJAHR", "FRUHJAHR")
+		d.mustAdd("Summer", "SOMMER", "SOMMERSEMESTER", "SOSE", "SS")
+		d.mustAdd("Fall", "HERBST")
+		return d
+	},
+	"fr": func() *SemesterDict {
+		d := NewSemesterDict()
+		d.mustAdd("Winter", "HIVER", "HIV")
+		d.mustAdd("Spring", "PRINTEMPS", "PRINT")
+		d.mustAdd("Summer", "ÉTÉ", "ETE")
+		d.mustAdd("Fall", "AUTOMNE", "AUT")
+		return d
+	},
+}
+
+// The locale dictionaries looked up while parsing, built once
+var localeSemesters = buildLocaleSemesters()
+
+func buildLocaleSemesters() map[string]*SemesterDict {
+	dicts := make(map[string]*SemesterDict)
+
+	for locale, build := range localeBuilders {
+		dicts[locale] = build()
+	}
+	return dicts
+}
+
+// LocaleSemesters returns a new copy of the builtin semester names of
+// locale, one of SemesterLocales
+func LocaleSemesters(locale string) (*SemesterDict, error) {
+	build, inMap := localeBuilders[strings.ToLower(locale)]
+	if !inMap {
+		return nil, fmt.Errorf("courseparser: no semester names for locale %q (expecting one of %s or %s)", locale, strings.Join(SemesterLocales, ", "), AutoLocale)
+	}
+	return build(), nil
+}
+
+// CheckLocale reports an error unless locale is a valid Parser.Locale
+func CheckLocale(locale string) error {
+	if locale == "" || strings.EqualFold(locale, AutoLocale) {
+		return nil
+	}
+	_, err := LocaleSemesters(locale)
+	return err
+}
+
+// WithLocale returns a copy of the Parser accepting the semester names of
+// locale, so one shared Parser can parse each input in its own locale
+func (p *Parser) WithLocale(locale string) *Parser {
+	q := *p
+	q.Locale = locale
+	return &q
+}
+
+// Locales the [Semester] is looked up in after the Semesters, in order
+func (p *Parser) locales() []string {
+	switch {
+	case p.Locale == "":
+		return nil
+	case strings.EqualFold(p.Locale, AutoLocale):
+		return SemesterLocales
+	}
+	return []string{strings.ToLower(p.Locale)}
+}
+
+// Looks a [Semester] name up in the Semesters, then in the dictionaries of
+// the Parser's locales, returning the canonical term and the locale of the
+// name ("" for the Semesters). A locale term the Semesters lack is skipped.
+func (p *Parser) lookupSemester(alias string) (string, string, bool) {
+	if term, inMap := p.Semesters.Lookup(alias); inMap {
+		return term, "", true
+	}
+	for _, locale := range p.locales() {
+		d := localeSemesters[locale]
+		if d == nil {
+			continue
+		}
+		if term, inMap := d.Lookup(alias); inMap && p.Semesters.hasTerm(term) {
+			return term, locale, true
+		}
+	}
+	return "", "", false
+}
+
+// Every spelling of a term the Parser accepts, for the suggestions
+func (p *Parser) semesterSpellings() map[string]string {
+	locales := p.locales()
+	if len(locales) == 0 {
+		return p.Semesters.aliases
+	}
+
+	spellings := make(map[string]string, len(p.Semesters.aliases))
+	for _, locale := range locales {
+		if d := localeSemesters[locale]; d != nil {
+			for alias, term := range d.aliases {
+				if p.Semesters.hasTerm(term) {
+					spellings[alias] = term
+				}
+			}
+		}
+	}
+	for alias, term := range p.Semesters.aliases {
+		spellings[alias] = term
+	}
+	return spellings
+}
@@ -0,0 +1,104 @@
+package courseparser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseLocale(t *testing.T) {
+	tests := []struct {
+		locale   string
+		input    string
+		semester string
+		named    string // CourseSelection.Locale
+		kind     error  // nil when the input parses
+	}{
+		{"es", "CS 111 Otoño 2019", "Fall", "es", nil},
+		{"es", "CS 111 otono 2019", "Fall", "es", nil},
+		{"ES", "CS 111 Primavera 2020", "Spring", "es", nil},
+		{"es", "CS 111 Herbst 2019", "", "", ErrInvalidSemester},
+		{"de", "CS 111 Herbst 2019", "Fall", "de", nil},
+		{"de", "CS 111 Frühling 2020", "Spring", "de", nil},
+		{"de", "CS 111 WS 2020", "Winter", "de", nil},
+		{"fr", "CS 111 Été 2020", "Summer", "fr", nil},
+		{"fr", "CS 111 Ete 2020", "Summer", "fr", nil},
+		{"fr", "Automne 2019 CS 111", "Fall", "fr", nil},
+		{AutoLocale, "CS 111 Otoño 2019", "Fall", "es", nil},
+		{AutoLocale, "CS 111 Sommer 2020", "Summer", "de", nil},
+		{AutoLocale, "CS 111 Hiver 2020", "Winter", "fr", nil},
+		// the Parser's own Semesters win
+		{"es", "CS 111 Fall 2019", "Fall", "", nil},
+		{AutoLocale, "CS 111 Winter 2020", "Winter", "", nil},
+		{"", "CS 111 Otoño 2019", "", "", ErrInvalidSemester},
+	}
+
+	p := testParser()
+	for _, test := range tests {
+		sel, err := p.WithLocale(test.locale).Parse(test.input)
+		if test.kind != nil {
+			if !errors.Is(err, test.kind) {
+				t.Errorf("%s %q: error %v, want %v", test.locale, test.input, err, test.kind)
+			}
+			continue
+		}
+		if err != nil || sel.Semester != test.semester || sel.Locale != test.named {
+			t.Errorf("%s %q: semester %q locale %q %v, want %s %q", test.locale, test.input, sel.Semester, sel.Locale, err, test.semester, test.named)
+		}
+	}
+	if p.Locale != "" {
+		t.Errorf("WithLocale changed the Parser's locale to %q", p.Locale)
+	}
+}
+
+// A locale name of a term the Semesters lack is not a semester
+func TestLocaleMissingTerm(t *testing.T) {
+	p := testParser()
+	p.Locale = "es"
+	p.Semesters = NewSemesterDict()
+	p.Semesters.Add("Fall")
+	p.Semesters.Add("Spring")
+
+	if sel, err := p.Parse("CS 111 Otoño 2019"); err != nil || sel.Semester != "Fall" {
+		t.Errorf("Otoño: %v %v", sel.Tokens(), err)
+	}
+	if _, err := p.Parse("CS 111 Invierno 2020"); !errors.Is(err, ErrInvalidSemester) {
+		t.Errorf("Invierno: error %v, want %v", err, ErrInvalidSemester)
+	}
+}
+
+func TestCheckLocale(t *testing.T) {
+	for _, locale := range []string{"", "auto", "AUTO", "es", "DE", "fr"} {
+		if err := CheckLocale(locale); err != nil {
+			t.Errorf("CheckLocale(%q) = %v", locale, err)
+		}
+	}
+	for _, locale := range []string{"xx", "en", "es-MX"} {
+		if err := CheckLocale(locale); err == nil {
+			t.Errorf("CheckLocale(%q): no error", locale)
+		}
+	}
+}
+
+// Each dictionary is a copy, changing one does not change the names parsed
+func TestLocaleSemestersCopies(t *testing.T) {
+	d, err := LocaleSemesters("FR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if term, ok := d.Lookup("AUTOMNE"); !ok || term != "Fall" {
+		t.Errorf("LocaleSemesters(FR) AUTOMNE = %q %v, want Fall", term, ok)
+	}
+	if err := d.Add("Summer", "SAISON"); err != nil {
+		t.Fatal(err)
+	}
+
+	again, _ := LocaleSemesters("fr")
+	if _, ok := again.Lookup("SAISON"); ok {
+		t.Error("LocaleSemesters(fr) shares its dictionary")
+	}
+	p := testParser()
+	p.Locale = "fr"
+	if _, err := p.Parse("CS 111 Saison 2020"); !errors.Is(err, ErrInvalidSemester) {
+		t.Errorf("Saison: error %v, want %v", err, ErrInvalidSemester)
+	}
+}
@@ -0,0 +1,320 @@
+package courseparser
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+//===========================================================
+//============ User Messages ================================
+//===========================================================
+// ParseError.Error() is the developer stack ("ERROR-950.35 - ..."). The
+// message shown to a student comes from a message catalog instead, keyed by
+// the error code of the most specific failure (e.g. "975.15"), else by the
+// failure kind of the token that failed, i.e. the outermost kind of the
+// stack (e.g. "invalid semester", the text of ErrInvalidSemester), else by
+// "default". Templates name their parameters in braces
+//
+//   "975.15": "'{token}' is not a semester."
+//
+//   {token}    : the word of the input at the failure, as typed
+//   {char}     : the offending character
+//   {year}, {earliest}, {latest}, {course}, {term}, {start}, {end},
+//   {max}, {expected}, {entry} : set by the errors they make sense for
+//
+// "suggestion" is the template of the "did you mean" sentence appended when
+// the error carries suggestions. English ("en") and Spanish ("es") are built
+// in, more languages load from files (see LoadMessages); a key missing from
+// a catalog is looked up in its Fallback, English by default.
+
+// Messages is the message catalog of one language
+type Messages struct {
+	Lang      string
+	Templates map[string]string // error code, failure kind, "default" or "suggestion" => template
+	Fallback  *Messages         // consulted for the keys missing from Templates, nil for none
+}
+
+// Languages of the builtin catalogs, see BuiltinMessages
+var MessageLanguages = []string{"en", "es"}
+
+var englishMessages = &Messages{Lang: "en", Templates: map[string]string{
+	"default":    "The course selection could not be read.",
+	"suggestion": "Did you mean {suggestion}?",
+
+	// Failure kinds
+	"invalid input structure":                "The course selection could not be read.",
+	"no input data":                          "Please enter a course selection, e.g. CS 111 Fall 2019.",
+	"invalid character":                      "The character '{char}' is not allowed here.",
+	"missing department":                     "The department is missing, e.g. CS in CS 111 Fall 2019.",
+	"invalid department":                     "'{token}' is not a valid department.",
+	"missing course":                         "The course number is missing, e.g. 111 in CS 111 Fall 2019.",
+	"invalid course":                         "'{token}' is not a valid course number.",
+	"invalid course suffix":                  "'{token}' is not a valid course suffix.",
+	"invalid section":                        "'{token}' is not a valid section number.",
+	"missing field separator":                "Please separate the course and the term, e.g. CS 111 Fall 2019.",
+	"more than one delimiter between fields": "Please use a single separator between the course and the term.",
+	"missing offer session":                  "The term is missing, e.g. Fall 2019.",
+	"missing semester":                       "The semester is missing, e.g. Fall 2019.",
+	"missing year":                           "The year is missing, e.g. Fall 2019.",
+	"invalid semester":                       "'{token}' is not a semester.",
+	"invalid year":                           "'{token}' is not a valid year.",
+	"year out of range":                      "'{token}' is outside the years on offer.",
+	"unknown department":                     "There is no department '{token}'.",
+	"unknown course":                         "There is no course '{token}'.",
+	"course not offered in term":             "The course is not offered in that term.",
+	"unexpected data after offer session":    "Unexpected '{token}' after the term, please enter one course selection at a time.",
+	"invalid term range":                     "The term range is not valid, e.g. Fall 2019 - Spring 2020.",
+	"invalid term code":                      "'{token}' is not a valid term code.",
+	"ambiguous input":                        "The entry can be read in more than one way, please enter the course number and the year.",
+
+	// Error codes
+	"500.30":   "The character '{char}' is not allowed here.",
+	"600.40":   "The character '{char}' is not allowed here.",
+	"650.40":   "The character '{char}' is not allowed here.",
+	"700.58":   "The course number is missing after the department, e.g. CS 111.",
+	"700.63":   "'{token}' is not a course number, e.g. 111 or 111L.",
+	"720.40":   "There is no department '{token}'.",
+	"750.40":   "There is no course {course}.",
+	"755.40":   "The course suffix '{token}' is longer than {max} letters.",
+	"760.40":   "The section number '{token}' is longer than {max} digits.",
+	"930.30":   "'{token}' is not a valid term code.",
+	"960.55":   "An academic year spans two consecutive years, e.g. AY 2019-20.",
+	"960.60":   "{year} is outside the years on offer, {earliest} to {latest}.",
+	"970.70":   "{year} is outside the years on offer, {earliest} to {latest}.",
+	"975.15":   "'{token}' is not a semester.",
+	"980.20":   "{course} is not offered in {term}.",
+	"980.30":   "{course} is not offered from {start} to {end}.",
+	"985.20":   "The term range must run forward, {start} is not before {end}.",
+	"1000.107": "Please enter a course selection, e.g. CS 111 Fall 2019.",
+	"1000.108": "Please enter a course selection, e.g. CS 111 Fall 2019.",
+	"1000.500": "The term is missing after the course, e.g. CS 111 Fall 2019.",
+	"1000.550": "Please separate the course and the term with {expected}, e.g. CS 111 Fall 2019.",
+	"1000.600": "Unexpected '{token}' after the term, please enter one course selection at a time.",
+	"1000.770": "Please use a single separator between the course and the term.",
+	"1200.20":  "No course comes before the term '{entry}', e.g. CS 111 Fall 2019, Spring 2020.",
+	"1200.30":  "No term follows '{entry}', e.g. CS 111, MATH 220 Fall 2019.",
+	"1300.60":  "Is {course} the course number or the year? Please enter both, e.g. CS 111 Fall 2019.",
+}}
+
+var spanishMessages = &Messages{Lang: "es", Fallback: englishMessages, Templates: map[string]string{
+	"default":    "No se pudo leer la selección de curso.",
+	"suggestion": "¿Quiso decir {suggestion}?",
+
+	// Failure kinds
+	"invalid input structure":                "No se pudo leer la selección de curso.",
+	"no input data":                          "Introduzca una selección de curso, p. ej. CS 111 Fall 2019.",
+	"invalid character":                      "El carácter '{char}' no está permitido aquí.",
+	"missing department":                     "Falta el departamento, p. ej. CS en CS 111 Fall 2019.",
+	"invalid department":                     "'{token}' no es un departamento válido.",
+	"missing course":                         "Falta el número de curso, p. ej. 111 en CS 111 Fall 2019.",
+	"invalid course":                         "'{token}' no es un número de curso válido.",
+	"invalid course suffix":                  "'{token}' no es un sufijo de curso válido.",
+	"invalid section":                        "'{token}' no es un número de sección válido.",
+	"missing field separator":                "Separe el curso y el periodo, p. ej. CS 111 Fall 2019.",
+	"more than one delimiter between fields": "Use un solo separador entre el curso y el periodo.",
+	"missing offer session":                  "Falta el periodo, p. ej. Fall 2019.",
+	"missing semester":                       "Falta el semestre, p. ej. Fall 2019.",
+	"missing year":                           "Falta el año, p. ej. Fall 2019.",
+	"invalid semester":                       "'{token}' no es un semestre.",
+	"invalid year":                           "'{token}' no es un año válido.",
+	"year out of range":                      "'{token}' está fuera de los años ofertados.",
+	"unknown department":                     "No existe el departamento '{token}'.",
+	"unknown course":                         "No existe el curso '{token}'.",
+	"course not offered in term":             "El curso no se ofrece en ese periodo.",
+	"unexpected data after offer session":    "'{token}' sobra después del periodo, introduzca una sola selección de curso cada vez.",
+	"invalid term range":                     "El rango de periodos no es válido, p. ej. Fall 2019 - Spring 2020.",
+	"invalid term code":                      "'{token}' no es un código de periodo válido.",
+	"ambiguous input":                        "La entrada admite más de una lectura, introduzca el número de curso y el año.",
+
+	// Error codes
+	"500.30":   "El carácter '{char}' no está permitido aquí.",
+	"600.40":   "El carácter '{char}' no está permitido aquí.",
+	"650.40":   "El carácter '{char}' no está permitido aquí.",
+	"700.58":   "Falta el número de curso después del departamento, p. ej. CS 111.",
+	"700.63":   "'{token}' no es un número de curso, p. ej. 111 o 111L.",
+	"720.40":   "No existe el departamento '{token}'.",
+	"750.40":   "No existe el curso {course}.",
+	"755.40":   "El sufijo de curso '{token}' tiene más de {max} letras.",
+	"760.40":   "El número de sección '{token}' tiene más de {max} dígitos.",
+	"930.30":   "'{token}' no es un código de periodo válido.",
+	"960.55":   "Un año académico abarca dos años consecutivos, p. ej. AY 2019-20.",
+	"960.60":   "{year} está fuera de los años ofertados, de {earliest} a {latest}.",
+	"970.70":   "{year} está fuera de los años ofertados, de {earliest} a {latest}.",
+	"975.15":   "'{token}' no es un semestre.",
+	"980.20":   "{course} no se ofrece en {term}.",
+	"980.30":   "{course} no se ofrece entre {start} y {end}.",
+	"985.20":   "El rango de periodos debe avanzar, {start} no es anterior a {end}.",
+	"1000.107": "Introduzca una selección de curso, p. ej. CS 111 Fall 2019.",
+	"1000.108": "Introduzca una selección de curso, p. ej. CS 111 Fall 2019.",
+	"1000.500": "Falta el periodo después del curso, p. ej. CS 111 Fall 2019.",
+	"1000.550": "Separe el curso y el periodo con {expected}, p. ej. CS 111 Fall 2019.",
+	"1000.600": "'{token}' sobra después del periodo, introduzca una sola selección de curso cada vez.",
+	"1000.770": "Use un solo separador entre el curso y el periodo.",
+	"1200.20":  "Ningún curso precede al periodo '{entry}', p. ej. CS 111 Fall 2019, Spring 2020.",
+	"1200.30":  "Ningún periodo sigue a '{entry}', p. ej. CS 111, MATH 220 Fall 2019.",
+	"1300.60":  "¿Es {course} el número de curso o el año? Introduzca ambos, p. ej. CS 111 Fall 2019.",
+}}
+
+// BuiltinMessages returns a new copy of the builtin catalog of lang, one of
+// MessageLanguages
+func BuiltinMessages(lang string) (*Messages, error) {
+	switch strings.ToLower(lang) {
+	case "en":
+		return englishMessages.clone(), nil
+	case "es":
+		return spanishMessages.clone(), nil
+	}
+	return nil, fmt.Errorf("courseparser: no builtin messages for language %q (expecting one of %s)", lang, strings.Join(MessageLanguages, ", "))
+}
+
+// Copy of the catalog and of its fallbacks
+func (m *Messages) clone() *Messages {
+	if m == nil {
+		return nil
+	}
+	q := &Messages{Lang: m.Lang, Templates: make(map[string]string, len(m.Templates)), Fallback: m.Fallback.clone()}
+	for key, text := range m.Templates {
+		q.Templates[key] = text
+	}
+	return q
+}
+
+// Message returns the user message of a Parse error in the catalog's
+// language, every Diagnostic's message for a recovering Parse. A nil
+// catalog is English.
+func (m *Messages) Message(err error) string {
+	var keys, kinds []string
+
+	if err == nil {
+		return ""
+	}
+	if m == nil {
+		m = englishMessages
+	}
+
+	if pe, ok := err.(*ParseError); ok && len(pe.Diagnostics) > 0 {
+		msgs := make([]string, len(pe.Diagnostics))
+		for i, d := range pe.Diagnostics {
+			msgs[i] = m.Message(d.Err)
+		}
+		return strings.Join(msgs, " ")
+	}
+
+	// The deepest codes of the stack are the most specific, and their
+	// parameters win
+	args := make(map[string]string)
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		pe, ok := e.(*ParseError)
+		if !ok {
+			continue
+		}
+		keys = append([]string{pe.Code}, keys...)
+		if pe.Kind != nil {
+			kinds = append(kinds, pe.Kind.Error())
+		}
+		for k, v := range pe.Args {
+			args[k] = v
+		}
+	}
+
+	msg := expand(m.template(append(append(keys, kinds...), "default")), args)
+	if suggestions := Suggestions(err); len(suggestions) > 0 {
+		msg += " " + expand(m.template([]string{"suggestion"}), map[string]string{"suggestion": suggestions[0].Text})
+	}
+	return msg
+}
+
+// Template of the first of keys the catalog (or else its Fallback) has
+func (m *Messages) template(keys []string) string {
+	for ; m != nil; m = m.Fallback {
+		for _, key := range keys {
+			if t, ok := m.Templates[key]; ok {
+				return t
+			}
+		}
+	}
+	return ""
+}
+
+// Fills the {name} parameters of a template
+func expand(template string, args map[string]string) string {
+	pairs := make([]string, 0, 2*len(args))
+	for k, v := range args {
+		pairs = append(pairs, "{"+k+"}", v)
+	}
+	return strings.NewReplacer(pairs...).Replace(template)
+}
+
+// Sets template parameters of the user message, as key value pairs
+func (e *ParseError) with(kv ...string) *ParseError {
+	if e.Args == nil {
+		e.Args = make(map[string]string)
+	}
+	for i := 0; i+1 < len(kv); i += 2 {
+		e.Args[kv[i]] = kv[i+1]
+	}
+	return e
+}
+
+// The word of src at a failure offset, or the offending character
+func wordAt(src []rune, offset int) string {
+	if offset < 0 || offset >= len(src) {
+		return ""
+	}
+	end := offset
+	for end < len(src) && (unicode.IsLetter(src[end]) || unicode.IsDigit(src[end]) || unicode.Is(unicode.Mn, src[end])) {
+		end++
+	}
+	if end == offset {
+		end++
+	}
+	return string(src[offset:end])
+}
+
+//===========================================================
+// Message files, one catalog each, its language the file name
+// (e.g. "fr.json"). The keys are those of the builtin catalogs, optionally
+// wrapped in a "messages" table (see config.go)
+//
+//   JSON : { "975.15": "'{token}' n'est pas un semestre." }
+//   YAML : "975.15": "'{token}' n'est pas un semestre."
+//   TOML : "975.15" = "'{token}' n'est pas un semestre."
+
+// LoadMessages reads a message catalog from a .json, .yaml, .yml or .toml
+// file, falling back to English
+func LoadMessages(path string) (*Messages, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	m, err := ReadMessages(f, strings.TrimPrefix(filepath.Ext(path), "."))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	m.Lang = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return m, nil
+}
+
+// ReadMessages reads a message catalog in format "json", "yaml" or "toml",
+// falling back to English
+func ReadMessages(r io.Reader, format string) (*Messages, error) {
+	entries, err := readConfig(r, format, "messages")
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Messages{Templates: make(map[string]string), Fallback: englishMessages.clone()}
+	for _, e := range entries {
+		if m.Templates[e.Key], err = e.text(); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,78 @@
+package courseparser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadMessages(t *testing.T) {
+	tests := []struct {
+		format string
+		file   string
+	}{
+		{"json", `{"975.15": "'{token}' n'est pas un semestre.", "suggestion": "Vouliez-vous dire {suggestion} ?"}`},
+		{"json", `{"messages": {"975.15": "'{token}' n'est pas un semestre."}}`},
+		{"yaml", "# French\n\"975.15\": \"'{token}' n'est pas un semestre.\"\nsuggestion: Vouliez-vous dire {suggestion} ?\n"},
+		{"yaml", "messages:\n  \"975.15\": \"'{token}' n'est pas un semestre.\"\n"},
+		{"toml", "\"975.15\" = \"'{token}' n'est pas un semestre.\" # French\n"},
+		{"toml", "[messages]\n\"975.15\" = \"'{token}' n'est pas un semestre.\"\n"},
+	}
+
+	for _, test := range tests {
+		m, err := ReadMessages(strings.NewReader(test.file), test.format)
+		if err != nil {
+			t.Errorf("%s %q: %v", test.format, test.file, err)
+			continue
+		}
+		if got := m.template([]string{"975.15"}); got != "'{token}' n'est pas un semestre." {
+			t.Errorf("%s %q: 975.15 = %q", test.format, test.file, got)
+		}
+		if got := m.template([]string{"default"}); got != englishMessages.Templates["default"] {
+			t.Errorf("%s %q: default = %q, want the English one", test.format, test.file, got)
+		}
+	}
+}
+
+func TestReadMessagesErrors(t *testing.T) {
+	tests := []struct {
+		format string
+		file   string
+		err    string
+	}{
+		{"toml", "\"975.15\" = \"#1\"\n[extra]\n", `courseparser: "extra": expecting a string, not a table`},
+		{"toml", "\"975.15\" = 3\n", `courseparser: "975.15": expecting a string, not a number`},
+		{"yaml", "\"975.15\": x\ndefault: [a, b]\n", `courseparser: line 2: "default": expecting a string, not a list`},
+		{"json", `{"975.15": null}`, `courseparser: line 1: "975.15": expecting a string, not null`},
+		{"json", `["975.15"]`, `courseparser: expecting a JSON object`},
+		{"ini", "", `courseparser: unknown messages format "ini"`},
+	}
+
+	for _, test := range tests {
+		_, err := ReadMessages(strings.NewReader(test.file), test.format)
+		if err == nil || err.Error() != test.err {
+			t.Errorf("%s %q: error %v, want %s", test.format, test.file, err, test.err)
+		}
+	}
+}
+
+// Each catalog is a copy, changing one does not change the builtin messages
+func TestBuiltinMessagesCopies(t *testing.T) {
+	es, err := BuiltinMessages("ES")
+	if err != nil || es.Lang != "es" || es.Fallback == nil || es.Fallback.Lang != "en" {
+		t.Fatalf("BuiltinMessages(ES) = %+v %v", es, err)
+	}
+	es.Templates["975.15"] = "changed"
+	es.Fallback.Templates["default"] = "changed"
+
+	again, _ := BuiltinMessages("es")
+	if again.Templates["975.15"] == "changed" || again.Fallback.Templates["default"] == "changed" {
+		t.Error("BuiltinMessages(es) shares its templates")
+	}
+	if en, _ := BuiltinMessages("en"); en.Templates["default"] == "changed" {
+		t.Error("BuiltinMessages(en) shares its templates")
+	}
+
+	if _, err := BuiltinMessages("fr"); err == nil {
+		t.Error("BuiltinMessages(fr): no error")
+	}
+}
@@ -0,0 +1,165 @@
+package courseparser
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// A Parser with a fixed year window, so the results do not depend on the clock
+func testParser() *Parser {
+	p := New()
+	p.Years = FixedYearWindow(2010, 2030)
+	return p
+}
+
+// Run with -race: one shared Parser parses thousands of inputs from many
+// goroutines, and every result must match the sequential one
+func TestParseConcurrent(t *testing.T) {
+	const workers = 16
+	const rounds = 200
+
+	p := testParser()
+	inputs := []string{
+		"CS 111 Fall 2016",
+		"CS-111 Spring 2019",
+		"MATH 220 S20",
+		"CS 111 2016 Fall",
+		"Fall 2019 CS 111",
+		"CS 111L-02 Su 2021",
+		"CS 111 Fxll 2016",
+		"CS 111 Fall 1990",
+		"CS#111 Fall 2016",
+		"ＣＳ １１１ Ｆａｌｌ ２０１９",
+		"CS 111 AY 2019-20",
+		"",
+	}
+
+	type outcome struct {
+		tokens string
+		err    string
+	}
+	run := func(input string) outcome {
+		sel, err := p.Parse(input)
+		o := outcome{tokens: joinTokens(sel.Tokens())}
+		if err != nil {
+			o.err = err.Error()
+		}
+		return o
+	}
+
+	want := make([]outcome, len(inputs))
+	for i, input := range inputs {
+		want[i] = run(input)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan string, workers)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for n := 0; n < rounds; n++ {
+				i := (w + n) % len(inputs)
+				if got := run(inputs[i]); got != want[i] {
+					errs <- inputs[i] + ": got " + got.tokens + " " + got.err + ", want " + want[i].tokens + " " + want[i].err
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+	for msg := range errs {
+		t.Error(msg)
+	}
+}
+
+// Parallel subtests sharing the package level Parser, with and without tracing
+func TestParseParallelSubtests(t *testing.T) {
+	traced := testParser()
+	traced.Logger = slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: LevelTrace}))
+
+	for i := 0; i < 8; i++ {
+		i := i
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Parallel()
+			for n := 0; n < 250; n++ {
+				p := traced
+				if n%2 == 0 {
+					p = defaultParser
+				}
+				year := time.Now().Year() - i
+				sel, err := p.Parse("CS 111 Fall " + strconv.Itoa(year))
+				if err != nil {
+					t.Fatalf("Parse: %v", err)
+				}
+				if sel.Dept != "CS" || sel.Course != "111" || sel.Semester != "Fall" || sel.Year != year {
+					t.Fatalf("Parse = %v", sel.Tokens())
+				}
+			}
+		})
+	}
+}
+
+func TestZeroParser(t *testing.T) {
+	year := strconv.Itoa(time.Now().Year())
+
+	for _, p := range []*Parser{
+		{},
+		{Layouts: DefaultLayouts()},
+		{Semesters: DefaultSemesters()},
+		{Years: YearWindow{Pivot: 50}},
+	} {
+		sel, err := p.Parse("CS-111 Fall " + year)
+		if err != nil {
+			t.Fatalf("%+v: Parse: %v", *p, err)
+		}
+		if got := joinTokens(sel.Tokens()); got != "CS 111 "+year+" Fall" {
+			t.Errorf("%+v: Parse = %q", *p, got)
+		}
+	}
+
+	var p Parser
+	if _, err := p.Parse("CS 111 Fxll " + year); !errors.Is(err, ErrInvalidSemester) {
+		t.Errorf("zero Parser: Parse error = %v, want %v", err, ErrInvalidSemester)
+	}
+	if results := p.ParseList("CS 111, MATH 220 Fall " + year); len(results) != 2 || results[0].Err != nil {
+		t.Errorf("zero Parser: ParseList = %+v", results)
+	}
+	if terms := p.ExpandRange(Term{"Fall", 2019}, Term{"Spring", 2020}); len(terms) != 3 {
+		t.Errorf("zero Parser: ExpandRange = %v", terms)
+	}
+
+	// A window with only a Pivot keeps it, and takes the default bounds
+	pivot := Parser{Years: YearWindow{Pivot: 50, Clock: func() time.Time { return time.Date(2005, 1, 1, 0, 0, 0, 0, time.UTC) }}}
+	if sel, err := pivot.Parse("CS 111 Fall 98"); err != nil || sel.Year != 1998 {
+		t.Errorf("Pivot 50: Parse = %v %v, want 1998", sel.Tokens(), err)
+	}
+}
+
+// A 2 digit year reads the same as a [Year] token and in a term code
+func TestPivotTermCode(t *testing.T) {
+	p := testParser()
+	p.Years = YearWindow{Earliest: 1990, Latest: 2030, Pivot: 50}
+	compact, _ := NewTermCodec("compact")
+	p.TermCodes = []TermCodec{compact}
+
+	for _, input := range []string{"CS 111 Fall 98", "CS 111 F98", "CS 111 F1998"} {
+		sel, err := p.Parse(input)
+		if err != nil || sel.Year != 1998 {
+			t.Errorf("%q: year %d %v, want 1998", input, sel.Year, err)
+		}
+	}
+	if sel, err := p.Parse("CS 111 F19 - SP21"); err != nil || sel.Range == nil || sel.Year != 2019 || sel.Range.End.Year != 2021 {
+		t.Errorf("F19 - SP21: %+v %v", sel, err)
+	}
+
+	if term, _ := compact.Decode("F98"); term.Year != 2098 {
+		t.Errorf("Decode(F98) = %v, want Fall 2098", term)
+	}
+}
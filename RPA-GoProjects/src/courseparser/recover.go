@@ -0,0 +1,182 @@
+package courseparser
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+//===========================================================
+//============ Error Recovery ===============================
+//===========================================================
+// A Parser with Recover set does not stop at the first failure. When a rule
+// of a sequence fails, the failure is recorded as a Diagnostic, the rest of
+// the offending token is skipped up to the next delimiter or Field
+// Separator, and parsing goes on with the next rule. Of the alternatives
+// and layouts, the one with the fewest Diagnostics wins. Parse() then fails
+// with one ParseError (1000.700) listing every Diagnostic in input order,
+// and returns the tokens that were valid, e.g. "XX-ABC Fal 1999" reports
+// both the [Course] and the [Year] and returns [XX  - Fall]. A [Semester]
+// where the [Course] is expected is not skipped, the course is missing and
+// the [OfferSession] follows, e.g. "CS Fall 2019" returns [CS  2019 Fall].
+
+// Diagnostic is one failure found by a recovering Parse
+type Diagnostic struct {
+	Token TokenKind // token the failure is about, NoToken for a separator or trailing data
+	Err   error     // the ParseError stack of the failure
+}
+
+// Failure kinds => the token they are about
+var kindTokens = map[error]TokenKind{
+	ErrMissingDept:     DeptToken,
+	ErrInvalidDept:     DeptToken,
+	ErrUnknownDept:     DeptToken,
+	ErrMissingCourse:   CourseToken,
+	ErrInvalidCourse:   CourseToken,
+	ErrInvalidSuffix:   CourseToken,
+	ErrInvalidSection:  CourseToken,
+	ErrUnknownCourse:   CourseToken,
+	ErrMissingYear:     YearToken,
+	ErrInvalidYear:     YearToken,
+	ErrYearOutOfRange:  YearToken,
+	ErrMissingSemester: SemesterToken,
+	ErrInvalidSemester: SemesterToken,
+}
+
+// Records the failure of rule as a Diagnostic, drops the token it was
+// extracting and skips the rest of the offending token up to the next
+// delimiter or Field Separator. A failed separator also skips the
+// separators, so the parse goes on with the next rule.
+//
+//funcid:1180
+func (p *Parser) recoverFrom(inStr *ChStr, sel *CourseSelection, rule *Rule, err error) {
+	start := inStr.indx
+
+	// The [OfferSession] came early, the delimiter before it is the Field Separator
+	if rule.Token == CourseToken && p.semesterAhead(inStr) {
+		missing := inStr.errorHere(codeOr(rule.Missing, "1100.20"), "Missing "+rule.name()+" Data ", ErrMissingCourse, nil)
+		inStr.addDiagnostic(Diagnostic{Token: CourseToken, Err: missing})
+		clearToken(rule.Token, sel)
+		for inStr.indx > 0 && p.isFieldDelimiter(inStr.data[inStr.indx-1]) {
+			inStr.indx--
+		}
+		p.trace(LevelTrace, "1180.50", "OUT : recoverFrom() missing", inStr, "rule", rule.name())
+		return
+	}
+
+	inStr.addDiagnostic(Diagnostic{Token: diagnosticToken(rule, err), Err: err})
+	clearToken(rule.Token, sel)
+
+	for inStr.indx < inStr.len && !p.isFieldDelimiter(inStr.data[inStr.indx]) {
+		inStr.indx++
+	}
+	if rule.Sep != NoSep {
+		p.skipSeparators(inStr)
+	}
+
+	p.trace(LevelTrace, "1180.90", "OUT : recoverFrom() skipped", inStr, "rule", rule.name(), "skipped", string(inStr.data[start:inStr.indx]))
+}
+
+// Reports whether c is a delimiter or the Field Separator
+func (p *Parser) isFieldDelimiter(c rune) bool {
+	return p.isDelimiter(c) || p.isFieldSeparator(c)
+}
+
+// Reports whether a [Semester] of the dictionary starts at the cursor
+func (p *Parser) semesterAhead(inStr *ChStr) bool {
+	end := inStr.indx
+	for end < inStr.len && isLetter(inStr.data[end]) {
+		end++
+	}
+	_, _, found := p.lookupSemester(strings.ToUpper(string(inStr.data[inStr.indx:end])))
+	return end > inStr.indx && found
+}
+
+// Reports whether only separators come before rune index indx
+func (inStr *ChStr) leading(p *Parser, indx int) bool {
+	for i := 0; i < indx && i < inStr.len; i++ {
+		if !p.isFieldDelimiter(inStr.data[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Skips the separators at the cursor, reporting whether any input follows them
+func (p *Parser) skipSeparators(inStr *ChStr) bool {
+	start := inStr.indx
+	for inStr.indx < inStr.len && p.isFieldDelimiter(inStr.data[inStr.indx]) {
+		inStr.indx++
+	}
+	return inStr.indx > start && inStr.indx < inStr.len
+}
+
+// Appends a Diagnostic, never sharing the backing array with a copy of the CH String
+func (inStr *ChStr) addDiagnostic(d Diagnostic) {
+	inStr.diags = append(inStr.diags[:len(inStr.diags):len(inStr.diags)], d)
+}
+
+// Token a failure is about: the token rule that failed, else the most
+// specific failure kind of the error stack
+func diagnosticToken(rule *Rule, err error) TokenKind {
+	var token TokenKind
+
+	switch rule.Token {
+	case DeptToken, CourseToken, YearToken, SemesterToken:
+		return rule.Token
+	}
+	for ; err != nil; err = errors.Unwrap(err) {
+		if pe, ok := err.(*ParseError); ok && pe.Kind != nil {
+			if k, inMap := kindTokens[pe.Kind]; inMap {
+				token = k
+			}
+		}
+	}
+	return token
+}
+
+// Drops the token(s) a failed token rule may have extracted
+func clearToken(kind TokenKind, sel *CourseSelection) {
+	switch kind {
+	case DeptToken:
+		sel.Dept = ""
+	case CourseToken:
+		sel.Course, sel.Suffix, sel.Section = "", "", ""
+	case YearToken:
+		sel.Year = 0
+	case SemesterToken:
+		sel.Semester, sel.Locale = "", ""
+	case AcademicYearToken, TermCodeToken:
+		sel.Semester, sel.Year, sel.Range, sel.Locale = "", 0, nil, ""
+	}
+}
+
+// A term range whose end did not parse stands for its start term
+func dropOpenRange(sel *CourseSelection) {
+	if sel.Range != nil && sel.Range.Terms == nil {
+		sel.Semester, sel.Year, sel.Range = sel.Range.Start.Semester, sel.Range.Start.Year, nil
+	}
+}
+
+// Error of a recovering Parse listing every Diagnostic
+func diagnosticsError(diags []Diagnostic) *ParseError {
+	msg := "1 problem found in the input"
+	if len(diags) > 1 {
+		msg = strconv.Itoa(len(diags)) + " problems found in the input"
+	}
+	return &ParseError{Code: "1000.700", Offset: -1, Msg: msg, Diagnostics: diags}
+}
+
+// Calls fn for every ParseError of err's stack and of its Diagnostics
+func walkErrors(err error, fn func(pe *ParseError)) {
+	for ; err != nil; err = errors.Unwrap(err) {
+		pe, ok := err.(*ParseError)
+		if !ok {
+			continue
+		}
+		fn(pe)
+		for _, d := range pe.Diagnostics {
+			walkErrors(d.Err, fn)
+		}
+	}
+}
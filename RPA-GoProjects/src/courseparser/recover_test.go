@@ -0,0 +1,68 @@
+package courseparser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRecover(t *testing.T) {
+	type diag struct {
+		token  TokenKind
+		kind   error
+		code   string
+		offset int
+	}
+	tests := []struct {
+		input  string
+		tokens string // Tokens() joined with "|", a dropped token is empty
+		diags  []diag
+	}{
+		{"XX-ABC Fal 1999", "XX|||Fall", []diag{
+			{CourseToken, ErrInvalidCourse, "700.63", 3},
+			{YearToken, ErrYearOutOfRange, "920.35", 11},
+		}},
+		// a session where the course is expected is not skipped
+		{"CS Fall 2019", "CS||2019|Fall", []diag{
+			{CourseToken, ErrMissingCourse, "700.58", 3},
+		}},
+		{"Fall 2019 CS", "CS||2019|Fall", []diag{
+			{CourseToken, ErrMissingCourse, "700.58", 12},
+		}},
+		{"CS 111 Fall", "CS|111||Fall", []diag{
+			{YearToken, ErrMissingYear, "800.38", 11},
+		}},
+		{"CS 111 Fxll 2019", "CS|111|2019|", []diag{
+			{SemesterToken, ErrInvalidSemester, "950.35", 7},
+		}},
+		{"CS 111 Fall 2019 junk", "CS|111|2019|Fall", []diag{
+			{NoToken, ErrTrailingData, "1000.600", 17},
+		}},
+	}
+
+	p := testParser()
+	p.Recover = true
+	for _, test := range tests {
+		sel, err := p.Parse(test.input)
+		if got := strings.Join(sel.Tokens(), "|"); got != test.tokens {
+			t.Errorf("%q: tokens %q, want %q", test.input, got, test.tokens)
+		}
+
+		var pe *ParseError
+		if !errors.As(err, &pe) || pe.Code != "1000.700" || len(pe.Diagnostics) != len(test.diags) {
+			t.Errorf("%q: error %v, want %d diagnostics", test.input, err, len(test.diags))
+			continue
+		}
+		for i, d := range pe.Diagnostics {
+			w := test.diags[i]
+			at := Locate(d.Err)
+			if d.Token != w.token || at == nil || at.Code != w.code || at.Offset != w.offset || w.kind != nil && !errors.Is(d.Err, w.kind) {
+				t.Errorf("%q: diagnostic %d %v %v at %+v, want %v %v code %s at %d", test.input, i, d.Token, d.Err, at, w.token, w.kind, w.code, w.offset)
+			}
+		}
+	}
+
+	if sel, err := p.Parse("CS 111 Fall 2019"); err != nil || joinTokens(sel.Tokens()) != "CS 111 2019 Fall" {
+		t.Errorf("valid input: %v %v", sel.Tokens(), err)
+	}
+}
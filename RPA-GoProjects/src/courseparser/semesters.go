@@ -0,0 +1,182 @@
+package courseparser
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//===========================================================
+//============ Semester Lookup Dictionary ===================
+//===========================================================
+
+// SemesterDict is a Semester Lookup Dictionary mapping the abbreviations a
+// user may type (the aliases) onto canonical term names. Terms keep the
+// order they were added in, which is taken to be their calendar order
+// within a year.
+type SemesterDict struct {
+	terms   []string
+	aliases map[string]string // upper case alias => term
+}
+
+// NewSemesterDict returns an empty dictionary
+func NewSemesterDict() *SemesterDict {
+	return &SemesterDict{aliases: make(map[string]string)}
+}
+
+// DefaultSemesters returns a new copy of the builtin Fall/Spring/Summer/Winter dictionary
+func DefaultSemesters() *SemesterDict {
+	d := NewSemesterDict()
+	d.mustAdd("Winter", "W", "WI", "WIN", "WTR", "WNTR", "WINTR", "WINTER")
+	d.mustAdd("Spring", "S", "SP", "SPR", "SPRG", "SPRNG", "SPRING")
+	d.mustAdd("Summer", "SU", "SUMR", "SUMMER")
+	d.mustAdd("Fall", "F", "FA", "FAL", "FALL")
+	return d
+}
+
+// Add registers term with the given aliases. A term whose name is a single
+// alpha token (e.g. "Fall") is an alias of itself, any other term (e.g.
+// "Summer I") needs an alias. Aliases are matched case insensitively and
+// must be letters only, because the [Semester] token is an alpha token. Add
+// fails, leaving the dictionary unchanged, when an alias is already mapped
+// to a different term.
+func (d *SemesterDict) Add(term string, aliases ...string) error {
+	if err := d.add(term, aliases...); err != nil {
+		return fmt.Errorf("courseparser: %w", err)
+	}
+	return nil
+}
+
+func (d *SemesterDict) add(term string, aliases ...string) error {
+	var keys []string
+
+	term = strings.TrimSpace(term)
+	if term == "" {
+		return fmt.Errorf("empty semester term")
+	}
+
+	if isAlphaWord(term) {
+		aliases = append([]string{term}, aliases...)
+	} else if len(aliases) == 0 {
+		return fmt.Errorf("semester term %q needs an alias, it is not a single word", term)
+	}
+
+	for _, alias := range aliases {
+		key := strings.ToUpper(strings.TrimSpace(alias))
+		if !isAlphaWord(key) {
+			return fmt.Errorf("semester alias %q of %q must be letters only", alias, term)
+		}
+		if other, inMap := d.aliases[key]; inMap && other != term {
+			return fmt.Errorf("semester alias %q maps to both %q and %q", alias, other, term)
+		}
+		keys = append(keys, key)
+	}
+
+	if !d.hasTerm(term) {
+		d.terms = append(d.terms, term)
+	}
+	for _, key := range keys {
+		d.aliases[key] = term
+	}
+	return nil
+}
+
+func (d *SemesterDict) mustAdd(term string, aliases ...string) {
+	if err := d.Add(term, aliases...); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup returns the term for an alias, ignoring case
+func (d *SemesterDict) Lookup(alias string) (string, bool) {
+	term, inMap := d.aliases[strings.ToUpper(alias)]
+	return term, inMap
+}
+
+// Terms returns the canonical terms in calendar order
+func (d *SemesterDict) Terms() []string {
+	return append([]string(nil), d.terms...)
+}
+
+// Aliases returns every upper case alias of term
+func (d *SemesterDict) Aliases(term string) []string {
+	var aliases []string
+
+	for alias, t := range d.aliases {
+		if t == term {
+			aliases = append(aliases, alias)
+		}
+	}
+	return aliases
+}
+
+func (d *SemesterDict) hasTerm(term string) bool {
+	for _, t := range d.terms {
+		if t == term {
+			return true
+		}
+	}
+	return false
+}
+
+func isAlphaWord(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !isLetter(r) {
+			return false
+		}
+	}
+	return true
+}
+
+//===========================================================
+//============ Semester Dictionary Config Files =============
+//===========================================================
+// All three formats describe the same "term => aliases" table, in calendar
+// order, optionally wrapped in a "semesters" table (see config.go)
+//
+//   JSON : { "Fall": ["F", "FA"], "Summer I": ["SUMI"] }
+//   YAML : Fall: [F, FA]              (or block lists "  - SUMI")
+//   TOML : Fall = ["F", "FA"]         ("Summer I" = ["SUMI"])
+//
+// A term named in several words, like "Summer I", is typed as one of its
+// aliases only, because the [Semester] token is a single alpha token.
+
+// LoadSemesters reads a semester dictionary from a .json, .yaml, .yml or .toml file
+func LoadSemesters(path string) (*SemesterDict, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	d, err := ReadSemesters(f, strings.TrimPrefix(filepath.Ext(path), "."))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return d, nil
+}
+
+// ReadSemesters reads a semester dictionary in format "json", "yaml" or "toml"
+func ReadSemesters(r io.Reader, format string) (*SemesterDict, error) {
+	entries, err := readConfig(r, format, "semesters")
+	if err != nil {
+		return nil, err
+	}
+
+	d := NewSemesterDict()
+	for _, e := range entries {
+		aliases, err := e.list()
+		if err != nil {
+			return nil, err
+		}
+		if err = d.add(e.Key, aliases...); err != nil {
+			return nil, e.errorf("%v", err)
+		}
+	}
+	return d, nil
+}
@@ -0,0 +1,70 @@
+package courseparser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadSemesters(t *testing.T) {
+	tests := []struct {
+		format string
+		file   string
+		terms  []string
+		alias  string // alias of the last term
+	}{
+		{"json", `{"Fall": ["F", "FA"], "Summer I": ["SUMI"]}`, []string{"Fall", "Summer I"}, "SUMI"},
+		{"json", `{"semesters": {"Winter": "W", "Fall": ["F"]}}`, []string{"Winter", "Fall"}, "F"},
+		{"yaml", "Fall: [F, FA]  # comment\n\"Summer I\":\n  - SUMI\n", []string{"Fall", "Summer I"}, "SUMI"},
+		{"yaml", "semesters:\n  Winter: W\n  Spring:\n  Fall: [F]\n", []string{"Winter", "Spring", "Fall"}, "F"},
+		{"toml", "Fall = [\"F\", \"FA\"] # comment\n\"Summer I\" = [\"SUMI\"]\n", []string{"Fall", "Summer I"}, "SUMI"},
+		{"toml", "[semesters]\nWinter = \"W\"\nSpring = [\n  \"S\", # spring\n  \"SP\",\n]\nFall = [\"F\"]\n", []string{"Winter", "Spring", "Fall"}, "F"},
+	}
+
+	for _, test := range tests {
+		d, err := ReadSemesters(strings.NewReader(test.file), test.format)
+		if err != nil {
+			t.Errorf("%s %q: %v", test.format, test.file, err)
+			continue
+		}
+		if got := d.Terms(); !reflect.DeepEqual(got, test.terms) {
+			t.Errorf("%s %q: Terms() = %q, want %q", test.format, test.file, got, test.terms)
+		}
+		last := test.terms[len(test.terms)-1]
+		if term, ok := d.Lookup(test.alias); !ok || term != last {
+			t.Errorf("%s %q: Lookup(%q) = %q, want %q", test.format, test.file, test.alias, term, last)
+		}
+	}
+}
+
+func TestReadSemestersErrors(t *testing.T) {
+	tests := []struct {
+		format string
+		file   string
+		err    string
+	}{
+		{"toml", "Fall = [\"F\"]\n[extra]\n", `courseparser: "extra": expecting a string or a list of strings, not a table`},
+		{"toml", "Fall = 3\n", `courseparser: "Fall": expecting a string or a list of strings, not a number`},
+		{"toml", "Fall = [\"F\", 1]\n", `courseparser: "Fall": expecting a string or a list of strings, not a list holding a number`},
+		{"toml", "Fall = [\"F#A\"]\n", `courseparser: "Fall": semester alias "F#A" of "Fall" must be letters only`},
+		{"yaml", "Winter: [W]\nFall: [F, W]\n", `courseparser: line 2: "Fall": semester alias "W" maps to both "Winter" and "Fall"`},
+		{"yaml", "Fall:\n  F: FA\n", `courseparser: line 1: "Fall": expecting a string or a list of strings, not a table`},
+		{"yaml", "- Fall\n", `courseparser: line 1: expecting a YAML mapping`},
+		{"json", "{\"Fall\": [\"F\"],\n \"Spring\": [\"F\"]}", `courseparser: line 2: "Spring": semester alias "F" maps to both "Fall" and "Spring"`},
+		{"yaml", "Fall: 3\n", `courseparser: line 1: "Fall": expecting a string or a list of strings, not a number`},
+		{"yaml", "Fall: [F, true]\n", `courseparser: line 1: "Fall": expecting a string or a list of strings, not a list holding a boolean`},
+		{"json", `["Fall"]`, `courseparser: expecting a JSON object`},
+		{"json", `{"Fall": ["F"]} {"Spring": ["S"]}`, `courseparser: line 1: unexpected text after the JSON object`},
+		{"json", "{\"Fall\": [\"F\"]", `courseparser: line 1: unexpected end of JSON input`},
+		{"json", "{\"Fall\": [\"F\"", `courseparser: line 1: unexpected end of JSON input`},
+		{"json", `{"Summer I": []}`, `courseparser: line 1: "Summer I": semester term "Summer I" needs an alias, it is not a single word`},
+		{"ini", "", `courseparser: unknown semesters format "ini"`},
+	}
+
+	for _, test := range tests {
+		_, err := ReadSemesters(strings.NewReader(test.file), test.format)
+		if err == nil || err.Error() != test.err {
+			t.Errorf("%s %q: error %v, want %s", test.format, test.file, err, test.err)
+		}
+	}
+}
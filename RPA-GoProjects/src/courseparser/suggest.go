@@ -0,0 +1,159 @@
+package courseparser
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+//===========================================================
+//============ "Did you mean" Suggestions ===================
+//===========================================================
+
+// Suggestion is a likely intended value for a misspelled token
+type Suggestion struct {
+	Text     string // canonical value, e.g. "Fall" or "CS"
+	Distance int    // edit distance from the typed token
+}
+
+// Correction records a token that was auto-corrected
+type Correction struct {
+	Token string `json:"token"` // "Dept" or "Semester"
+	From  string `json:"from"`  // as typed
+	To    string `json:"to"`    // as corrected
+}
+
+// Default Parser.SuggestDistance
+const DefaultSuggestDistance = 2
+
+// Suggestions returns the suggestions carried by the first ParseError in
+// err's chain (or in its Diagnostics) that has any
+func Suggestions(err error) []Suggestion {
+	for err != nil {
+		pe, ok := err.(*ParseError)
+		if ok && len(pe.Suggestions) > 0 {
+			return pe.Suggestions
+		}
+		for i := 0; ok && i < len(pe.Diagnostics); i++ {
+			if s := Suggestions(pe.Diagnostics[i].Err); s != nil {
+				return s
+			}
+		}
+		err = errors.Unwrap(err)
+	}
+	return nil
+}
+
+// SuggestSemester returns the terms whose aliases (or names in the Parser's
+// locales) are within the Parser's edit distance of a misspelled alias, best first
+func (p *Parser) SuggestSemester(alias string) []Suggestion {
+	p = p.withDefaults()
+	return p.suggest(alias, p.semesterSpellings())
+}
+
+// SuggestDept returns the catalog departments within the Parser's edit
+// distance of a misspelled department code, best first
+func (p *Parser) SuggestDept(dept string) []Suggestion {
+	codes := make(map[string]string)
+
+	if p.Catalog == nil {
+		return nil
+	}
+	for _, code := range p.Catalog.order {
+		codes[code] = code
+	}
+	return p.suggest(dept, codes)
+}
+
+// Ranks candidates by edit distance from token. Each candidate is a
+// (spelling, canonical value) pair, a value is suggested once at the
+// distance of its closest spelling. Short tokens allow fewer edits, at most
+// half their length, so "X" suggests nothing rather than every 1 letter
+// abbreviation.
+func (p *Parser) suggest(token string, spellings map[string]string) []Suggestion {
+	var suggestions []Suggestion
+
+	maxDist := p.SuggestDistance
+	if n := len([]rune(token)) / 2; n < maxDist {
+		maxDist = n
+	}
+	if maxDist <= 0 {
+		return nil
+	}
+
+	best := make(map[string]int)
+	token = strings.ToUpper(token)
+	for spelling, value := range spellings {
+		dist := editDistance(token, strings.ToUpper(spelling))
+		if dist > maxDist {
+			continue
+		}
+		if d, seen := best[value]; !seen || dist < d {
+			best[value] = dist
+		}
+	}
+
+	for value, dist := range best {
+		suggestions = append(suggestions, Suggestion{Text: value, Distance: dist})
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Distance != suggestions[j].Distance {
+			return suggestions[i].Distance < suggestions[j].Distance
+		}
+		return suggestions[i].Text < suggestions[j].Text
+	})
+	return suggestions
+}
+
+// Returns the single best suggestion when it is close enough to auto-correct
+func (p *Parser) autoCorrection(suggestions []Suggestion) (string, bool) {
+	if p.AutoCorrect <= 0 || len(suggestions) == 0 || suggestions[0].Distance > p.AutoCorrect {
+		return "", false
+	}
+	if len(suggestions) > 1 && suggestions[1].Distance == suggestions[0].Distance {
+		return "", false
+	}
+	return suggestions[0].Text, true
+}
+
+// Formats suggestions for the developer error stack
+func didYouMean(suggestions []Suggestion) string {
+	var texts []string
+
+	if len(suggestions) == 0 {
+		return ""
+	}
+	for _, s := range suggestions {
+		texts = append(texts, s.Text)
+	}
+	return " (did you mean " + strings.Join(texts, " or ") + "?)"
+}
+
+// Optimal string alignment distance: insertions, deletions, substitutions
+// and transpositions of adjacent characters ("SPIRNG" => "SPRING") cost 1
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
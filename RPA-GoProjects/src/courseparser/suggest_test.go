@@ -0,0 +1,115 @@
+package courseparser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"FALL", "FALL", 0},
+		{"FXLL", "FALL", 1},
+		{"FAL", "FALL", 1},
+		{"SPIRNG", "SPRING", 1},
+		{"SRPING", "SPRING", 1},
+		{"FXXL", "FALL", 2},
+		{"", "CS", 2},
+		{"ÉTÉ", "ETE", 2},
+	}
+	for _, test := range tests {
+		if got := editDistance(test.a, test.b); got != test.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+// Suggestion texts of a Parser misspelling, best first
+func suggestionTexts(suggestions []Suggestion) []string {
+	texts := []string{}
+	for _, s := range suggestions {
+		texts = append(texts, s.Text)
+	}
+	return texts
+}
+
+func TestSuggest(t *testing.T) {
+	p := testParser()
+	p.Catalog = NewCatalog()
+	for _, dept := range []string{"CS", "CE", "MATH", "PHYS"} {
+		p.Catalog.AddDept(dept, "")
+	}
+
+	tests := []struct {
+		suggest  func(string) []Suggestion
+		token    string
+		distance int
+		want     string
+	}{
+		{p.SuggestSemester, "Fxll", 2, "Fall"},
+		{p.SuggestSemester, "Sprnig", 2, "Spring"},
+		{p.SuggestSemester, "Fxxl", 2, "Fall"},
+		{p.SuggestSemester, "Fxxl", 1, ""},
+		{p.SuggestSemester, "Fxll", 0, ""},
+		{p.SuggestSemester, "X", 2, ""}, // at most half the token's length
+		{p.SuggestSemester, "Qqqqqq", 2, ""},
+		{p.SuggestDept, "CX", 2, "CE CS"},
+		{p.SuggestDept, "MAHT", 2, "MATH"},
+		{p.SuggestDept, "PYHS", 1, "PHYS"},
+		{p.SuggestDept, "BIO", 2, ""},
+	}
+	for _, test := range tests {
+		p.SuggestDistance = test.distance
+		if got := joinTokens(suggestionTexts(test.suggest(test.token))); got != test.want {
+			t.Errorf("%q within %d: suggestions %q, want %q", test.token, test.distance, got, test.want)
+		}
+	}
+
+	if got := (&Parser{}).SuggestDept("CX"); got != nil {
+		t.Errorf("SuggestDept without a Catalog = %v", got)
+	}
+}
+
+func TestAutoCorrect(t *testing.T) {
+	p := testParser()
+	p.Catalog = NewCatalog()
+	for _, dept := range []string{"CS", "CE", "MATH"} {
+		p.Catalog.AddCourse(dept, "111")
+	}
+
+	tests := []struct {
+		input       string
+		autoCorrect int
+		want        string // tokens, "" when the input must fail
+		corrections int
+	}{
+		{"CS 111 Fxll 2016", 1, "CS 111 2016 Fall", 1},
+		{"CS 111 Fxll 2016", 0, "", 0},
+		{"CS 111 Fxxl 2016", 1, "", 0},
+		{"CS 111 Fxxl 2016", 2, "CS 111 2016 Fall", 1},
+		{"MAHT 111 Fall 2016", 1, "MATH 111 2016 Fall", 1},
+		{"MAHT 111 Fxll 2016", 1, "MATH 111 2016 Fall", 2},
+		{"CX 111 Fall 2016", 1, "", 0}, // CE and CS tie
+		{"CS 111 Fall 2016", 2, "CS 111 2016 Fall", 0},
+	}
+	for _, test := range tests {
+		p.AutoCorrect = test.autoCorrect
+		sel, err := p.Parse(test.input)
+		if test.want == "" {
+			if err == nil || len(Suggestions(err)) == 0 {
+				t.Errorf("%q autocorrect %d: %v %v, want an error with suggestions", test.input, test.autoCorrect, sel.Tokens(), err)
+			}
+			continue
+		}
+		if err != nil || joinTokens(sel.Tokens()) != test.want || len(sel.Corrections) != test.corrections {
+			t.Errorf("%q autocorrect %d: %v %+v %v, want %s with %d corrections", test.input, test.autoCorrect, sel.Tokens(), sel.Corrections, err, test.want, test.corrections)
+		}
+	}
+
+	p.AutoCorrect = 1
+	if _, err := p.Parse("CX 111 Fall 2016"); !errors.Is(err, ErrUnknownDept) || joinTokens(suggestionTexts(Suggestions(err))) != "CE CS" {
+		t.Errorf("CX: %v, want %v suggesting CE CS", err, ErrUnknownDept)
+	}
+}
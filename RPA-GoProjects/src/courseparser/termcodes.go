@@ -0,0 +1,241 @@
+package courseparser
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+//===========================================================
+//============ Institutional Term Codes =====================
+//===========================================================
+// Student information systems name a term by a code rather than by its
+// Semester and Year, e.g. Fall 2019 is
+//
+//   banner     : "201990"  year + 2 digit term code
+//   peoplesoft : "2199"    century (1 = 19xx, 2 = 20xx) + 2 digit year + 1 digit term code
+//   compact    : "F19"     semester abbreviation + 2 (or 4) digit year
+//
+// A TermCodec converts between the two. The codecs in Parser.TermCodes are
+// accepted as the [OfferSession] (or either end of a term range), and the
+// first of them formats CourseSelection.TermCode so parsed output can be fed
+// straight into the SIS. The term codes of each codec are configurable, the
+// defaults below follow the calendar order Winter, Spring, Summer, Fall.
+
+// TermCodec parses and formats the term codes of one institution
+type TermCodec interface {
+	Name() string                     // e.g. "banner"
+	Decode(code string) (Term, error) // "201990" => Fall 2019
+	Encode(t Term) (string, error)    // Fall 2019 => "201990"
+}
+
+// WindowDecoder is implemented by a TermCodec whose codes may hold a 2 digit
+// year. The Parser decodes with DecodeIn so the year is expanded around its
+// own Years.Pivot, like a 2 digit [Year] token.
+type WindowDecoder interface {
+	DecodeIn(code string, w YearWindow) (Term, error)
+}
+
+// Names of the builtin codecs, see NewTermCodec
+var TermCodecNames = []string{"banner", "peoplesoft", "compact"}
+
+// NewTermCodec returns the builtin codec name with its default term codes
+func NewTermCodec(name string) (TermCodec, error) {
+	switch strings.ToLower(name) {
+	case "banner":
+		return &BannerCodec{Codes: map[string]string{"Winter": "10", "Spring": "20", "Summer": "50", "Fall": "90"}}, nil
+	case "peoplesoft":
+		return &PeopleSoftCodec{Codes: map[string]string{"Winter": "1", "Spring": "2", "Summer": "6", "Fall": "9"}}, nil
+	case "compact":
+		return &CompactCodec{Codes: map[string]string{"Winter": "W", "Spring": "SP", "Summer": "SU", "Fall": "F"}}, nil
+	}
+	return nil, fmt.Errorf("courseparser: unknown term code %q (expecting one of %s)", name, strings.Join(TermCodecNames, ", "))
+}
+
+// BannerCodec formats a term as its 4 digit year and a 2 digit term code
+type BannerCodec struct {
+	Codes map[string]string // semester => 2 digit term code
+}
+
+func (c *BannerCodec) Name() string {
+	return "banner"
+}
+
+func (c *BannerCodec) Decode(code string) (Term, error) {
+	if len(code) != 6 || !isDigits(code) {
+		return Term{}, fmt.Errorf("courseparser: banner term code %q must be 6 digits", code)
+	}
+	year, _ := strconv.Atoi(code[:4])
+	semester, ok := semesterOf(c.Codes, code[4:])
+	if !ok {
+		return Term{}, fmt.Errorf("courseparser: unknown banner term code %q in %q", code[4:], code)
+	}
+	return Term{Semester: semester, Year: year}, nil
+}
+
+func (c *BannerCodec) Encode(t Term) (string, error) {
+	code, ok := c.Codes[t.Semester]
+	if !ok || t.Year < 1000 || t.Year > 9999 {
+		return "", fmt.Errorf("courseparser: no banner term code for %v", t)
+	}
+	return strconv.Itoa(t.Year) + code, nil
+}
+
+// PeopleSoftCodec formats a term as "CYYT": a century digit (1 = 19xx,
+// 2 = 20xx), the 2 digit year and a 1 digit term code
+type PeopleSoftCodec struct {
+	Codes map[string]string // semester => 1 digit term code
+}
+
+func (c *PeopleSoftCodec) Name() string {
+	return "peoplesoft"
+}
+
+func (c *PeopleSoftCodec) Decode(code string) (Term, error) {
+	if len(code) != 4 || !isDigits(code) || code[0] == '0' {
+		return Term{}, fmt.Errorf("courseparser: peoplesoft term code %q must be 4 digits CYYT", code)
+	}
+	yy, _ := strconv.Atoi(code[1:3])
+	semester, ok := semesterOf(c.Codes, code[3:])
+	if !ok {
+		return Term{}, fmt.Errorf("courseparser: unknown peoplesoft term code %q in %q", code[3:], code)
+	}
+	return Term{Semester: semester, Year: 1800 + int(code[0]-'0')*100 + yy}, nil
+}
+
+func (c *PeopleSoftCodec) Encode(t Term) (string, error) {
+	code, ok := c.Codes[t.Semester]
+	century := t.Year/100 - 18
+	if !ok || century < 1 || century > 9 {
+		return "", fmt.Errorf("courseparser: no peoplesoft term code for %v", t)
+	}
+	return strconv.Itoa(century) + fmt.Sprintf("%02d", t.Year%100) + code, nil
+}
+
+// CompactCodec formats a term as a semester abbreviation and the year,
+// e.g. "F19", or "F2019" with FullYear. Decode also accepts 4 digit years
+// and reads 2 digit years as 20yy, DecodeIn expands them around the window's
+// Pivot instead.
+type CompactCodec struct {
+	Codes    map[string]string // semester => abbreviation
+	FullYear bool
+}
+
+func (c *CompactCodec) Name() string {
+	return "compact"
+}
+
+func (c *CompactCodec) Decode(code string) (Term, error) {
+	return c.DecodeIn(code, YearWindow{})
+}
+
+func (c *CompactCodec) DecodeIn(code string, w YearWindow) (Term, error) {
+	i := strings.IndexFunc(code, func(r rune) bool { return isNumber(r) })
+	if i <= 0 || !isDigits(code[i:]) || len(code)-i != 2 && len(code)-i != 4 {
+		return Term{}, fmt.Errorf("courseparser: compact term code %q must be letters and a 2 or 4 digit year", code)
+	}
+	semester, ok := semesterOf(c.Codes, code[:i])
+	if !ok {
+		return Term{}, fmt.Errorf("courseparser: unknown semester abbreviation %q in %q", code[:i], code)
+	}
+	year, _ := strconv.Atoi(code[i:])
+	return Term{Semester: semester, Year: w.Expand(year, len(code)-i)}, nil
+}
+
+func (c *CompactCodec) Encode(t Term) (string, error) {
+	code, ok := c.Codes[t.Semester]
+	if !ok || t.Year < 1000 || t.Year > 9999 {
+		return "", fmt.Errorf("courseparser: no compact term code for %v", t)
+	}
+	if c.FullYear {
+		return code + strconv.Itoa(t.Year), nil
+	}
+	return code + fmt.Sprintf("%02d", t.Year%100), nil
+}
+
+// Reverse lookup of a term code, ignoring case
+func semesterOf(codes map[string]string, code string) (string, bool) {
+	for semester, c := range codes {
+		if strings.EqualFold(c, code) {
+			return semester, true
+		}
+	}
+	return "", false
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if !isNumber(r) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// Term returns the [OfferSession] of the selection, the start term of a range
+func (cs CourseSelection) Term() Term {
+	return Term{Semester: cs.Semester, Year: cs.Year}
+}
+
+// Parse and Extract an institutional term code (see Parser.TermCodes) as
+// the [Semester] and [Year] tokens
+//
+//funcid:930
+func (p *Parser) getTermCodeToken(inStr *ChStr, sel *CourseSelection) error {
+	var code string
+	var err error
+
+	p.trace(slog.LevelDebug, "930.10", "IN- : getTermCodeToken()", inStr)
+
+	start := inStr.indx
+	for inStr.indx < inStr.len && isAlphaNumeric(inStr.data[inStr.indx]) {
+		code += string(inStr.data[inStr.indx])
+		inStr.indx++
+	}
+	if code == "" {
+		return inStr.errorHere("930.20", "Non Alpha Numeric first character in Term Code ==> '"+string(inStr.data[inStr.indx])+"'", ErrInvalidCharacter, nil)
+	}
+
+	for _, codec := range p.TermCodes {
+		var term Term
+
+		if wd, ok := codec.(WindowDecoder); ok {
+			term, err = wd.DecodeIn(code, p.Years)
+		} else {
+			term, err = codec.Decode(code)
+		}
+		if err != nil {
+			continue
+		}
+		semester, inMap := p.Semesters.Lookup(term.Semester)
+		if !inMap {
+			return inStr.errorAt("930.40", start, "Term Code "+code+" is a "+term.Semester+" term, not in the Semester Lookup Dictionary", ErrInvalidSemester, nil)
+		}
+		if sel.Year, err = p.validateYear(strconv.Itoa(term.Year)); err != nil {
+			return inStr.errorAt("930.45", start, "Invalid Year of Term Code "+code, nil, err)
+		}
+		sel.Semester = semester
+
+		p.trace(slog.LevelDebug, "930.90", "OUT : getTermCodeToken()", inStr, "codec", codec.Name(), "code", code, "term", sel.Term().String())
+		return nil
+	}
+	return inStr.errorAt("930.30", start, "Invalid Term Code "+code, ErrInvalidTermCode, err)
+}
+
+// Formats the term code(s) of the selection with the first of Parser.TermCodes
+func (p *Parser) encodeTerms(sel *CourseSelection) {
+	if len(p.TermCodes) == 0 || sel.Semester == "" {
+		return
+	}
+
+	codec := p.TermCodes[0]
+	sel.TermCode, _ = codec.Encode(sel.Term())
+	if sel.Range != nil {
+		sel.Range.Codes = nil
+		for _, term := range sel.Range.Terms {
+			code, _ := codec.Encode(term)
+			sel.Range.Codes = append(sel.Range.Codes, code)
+		}
+	}
+}
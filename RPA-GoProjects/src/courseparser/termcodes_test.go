@@ -0,0 +1,132 @@
+package courseparser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTermCodecs(t *testing.T) {
+	tests := []struct {
+		codec string
+		code  string
+		term  Term
+	}{
+		{"banner", "201990", Term{"Fall", 2019}},
+		{"banner", "202010", Term{"Winter", 2020}},
+		{"banner", "199850", Term{"Summer", 1998}},
+		{"peoplesoft", "2199", Term{"Fall", 2019}},
+		{"peoplesoft", "2202", Term{"Spring", 2020}},
+		{"peoplesoft", "1986", Term{"Summer", 1998}},
+		{"compact", "F19", Term{"Fall", 2019}},
+		{"compact", "SP20", Term{"Spring", 2020}},
+		{"compact", "W21", Term{"Winter", 2021}},
+	}
+	for _, test := range tests {
+		codec, err := NewTermCodec(test.codec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if term, err := codec.Decode(test.code); err != nil || term != test.term {
+			t.Errorf("%s: Decode(%q) = %v %v, want %v", test.codec, test.code, term, err, test.term)
+		}
+		if code, err := codec.Encode(test.term); err != nil || code != test.code {
+			t.Errorf("%s: Encode(%v) = %q %v, want %q", test.codec, test.term, code, err, test.code)
+		}
+	}
+
+	if _, err := NewTermCodec("colleague"); err == nil || !strings.Contains(err.Error(), "banner, peoplesoft, compact") {
+		t.Errorf("NewTermCodec(colleague) error %v", err)
+	}
+}
+
+func TestTermCodecErrors(t *testing.T) {
+	tests := []struct {
+		codec string
+		code  string
+	}{
+		{"banner", "20199"},
+		{"banner", "2019AB"},
+		{"banner", "201930"},
+		{"peoplesoft", "0199"},
+		{"peoplesoft", "21990"},
+		{"peoplesoft", "2193"},
+		{"compact", "19"},
+		{"compact", "F199"},
+		{"compact", "X19"},
+		{"compact", "F19X"},
+	}
+	for _, test := range tests {
+		codec, _ := NewTermCodec(test.codec)
+		if term, err := codec.Decode(test.code); err == nil {
+			t.Errorf("%s: Decode(%q) = %v, want an error", test.codec, test.code, term)
+		}
+	}
+
+	banner, _ := NewTermCodec("banner")
+	for _, term := range []Term{{"Autumn", 2019}, {"Fall", 19}} {
+		if code, err := banner.Encode(term); err == nil {
+			t.Errorf("banner: Encode(%v) = %q, want an error", term, code)
+		}
+	}
+}
+
+func TestCompactFullYear(t *testing.T) {
+	codec := &CompactCodec{Codes: map[string]string{"Fall": "FA"}, FullYear: true}
+
+	if code, err := codec.Encode(Term{"Fall", 2019}); err != nil || code != "FA2019" {
+		t.Errorf("Encode = %q %v, want FA2019", code, err)
+	}
+	if term, err := codec.Decode("fa2019"); err != nil || term != (Term{"Fall", 2019}) {
+		t.Errorf("Decode(fa2019) = %v %v", term, err)
+	}
+	if term, _ := codec.DecodeIn("FA98", YearWindow{Pivot: 50}); term.Year != 1998 {
+		t.Errorf("DecodeIn(FA98) = %v, want Fall 1998", term)
+	}
+}
+
+// Term codes are an [OfferSession], and the first codec formats the output
+func TestParseTermCodes(t *testing.T) {
+	p := testParser()
+	for _, name := range []string{"banner", "compact"} {
+		codec, _ := NewTermCodec(name)
+		p.TermCodes = append(p.TermCodes, codec)
+	}
+
+	tests := []struct {
+		input    string
+		term     Term
+		termCode string
+		codes    string // term codes of a range
+	}{
+		{"CS 111 201990", Term{"Fall", 2019}, "201990", ""},
+		{"CS 111 F19", Term{"Fall", 2019}, "201990", ""},
+		{"CS 111 Fall 2019", Term{"Fall", 2019}, "201990", ""},
+		{"201990 CS 111", Term{"Fall", 2019}, "201990", ""},
+		{"CS 111 201990 - SP20", Term{"Fall", 2019}, "201990", "201990 202010 202020"},
+	}
+	for _, test := range tests {
+		sel, err := p.Parse(test.input)
+		if err != nil || sel.Term() != test.term || sel.TermCode != test.termCode {
+			t.Errorf("%q: %v %q %v, want %v %q", test.input, sel.Term(), sel.TermCode, err, test.term, test.termCode)
+			continue
+		}
+		if test.codes != "" && (sel.Range == nil || strings.Join(sel.Range.Codes, " ") != test.codes) {
+			t.Errorf("%q: range %+v, want codes %s", test.input, sel.Range, test.codes)
+		}
+	}
+
+	for _, test := range []struct {
+		input string
+		kind  error
+	}{
+		{"CS 111 209990", ErrYearOutOfRange},
+		// a code no codec decodes is read as a [Year] or a [Semester]
+		{"CS 111 201930", ErrYearOutOfRange},
+		{"CS 111 X19", ErrInvalidSemester},
+	} {
+		if _, err := p.Parse(test.input); !errors.Is(err, test.kind) {
+			t.Errorf("%q: error %v, want %v", test.input, err, test.kind)
+		}
+	}
+}
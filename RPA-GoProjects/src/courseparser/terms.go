@@ -0,0 +1,214 @@
+package courseparser
+
+import (
+	"log/slog"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+//===========================================================
+//============ Term Ranges and Academic Years ===============
+//===========================================================
+// Besides a single term, the [OfferSession] may be a term range
+//
+//   "Fall 2019 - Spring 2020", "F19-S21"  : [Term] '-' [Term]
+//   "AY 2019-20"                          : the terms of an academic year
+//
+// The range is kept in CourseSelection.Range, expanded to its terms in the
+// calendar order of the Semester Lookup Dictionary, while Semester and Year
+// hold its start term. The start must precede the end, and both years must
+// be inside the Parser's YearWindow.
+
+// Term is one semester of one year, e.g. Fall 2019
+type Term struct {
+	Semester string
+	Year     int
+}
+
+// String returns the term as "Fall 2019"
+func (t Term) String() string {
+	return t.Semester + " " + strconv.Itoa(t.Year)
+}
+
+// TermRange is a range of terms, both ends included
+type TermRange struct {
+	Start Term
+	End   Term
+	Terms []Term   // every term from Start to End, in calendar order
+	Codes []string // term codes of Terms, when the Parser has TermCodes
+
+	endAt int // input position of the end term while parsing it
+}
+
+// Characters separating the start and end of a term range ('-' or an en dash)
+const RangeSeparators = "-–"
+
+// Keyword of an academic year "AY 2019-20"
+const AcademicYearKeyword = "AY"
+
+// Default Parser.AcademicYearStart
+const DefaultAcademicYearStart = "Fall"
+
+// ExpandRange lists the terms from start to end, both included, in the
+// calendar order of the Semester Lookup Dictionary
+func (p *Parser) ExpandRange(start, end Term) []Term {
+	return p.withDefaults().expandRange(start, end)
+}
+
+func (p *Parser) expandRange(start, end Term) []Term {
+	var terms []Term
+
+	semesters := p.Semesters.Terms()
+	if len(semesters) == 0 {
+		return nil
+	}
+	for i := p.termIndex(start); i <= p.termIndex(end); i++ {
+		terms = append(terms, Term{Semester: semesters[i%len(semesters)], Year: i / len(semesters)})
+	}
+	return terms
+}
+
+// Position of term in the sequence of all terms, -1 for an unknown semester
+func (p *Parser) termIndex(t Term) int {
+	semesters := p.Semesters.Terms()
+	for i, semester := range semesters {
+		if semester == t.Semester {
+			return t.Year*len(semesters) + i
+		}
+	}
+	return -1
+}
+
+// Length of the term range separator at the cursor, i.e. optional spaces,
+// '-', optional spaces followed by the end term. 0 when there is none.
+func (p *Parser) rangeSepLen(inStr *ChStr) int {
+	i := inStr.indx
+	for i < inStr.len && unicode.IsSpace(inStr.data[i]) {
+		i++
+	}
+	if i >= inStr.len || !strings.ContainsRune(RangeSeparators, inStr.data[i]) {
+		return 0
+	}
+	i++
+	for i < inStr.len && unicode.IsSpace(inStr.data[i]) {
+		i++
+	}
+	if i >= inStr.len || !(isLetter(inStr.data[i]) || isNumber(inStr.data[i])) {
+		return 0
+	}
+	return i - inStr.indx
+}
+
+// Parse and Extract an Academic Year "AY 2019-20" as the range of its terms,
+// from Parser.AcademicYearStart up to the term before it in the next year
+//
+//funcid:960
+func (p *Parser) getAcademicYearToken(inStr *ChStr, sel *CourseSelection) error {
+	var first, last int
+	var err error
+
+	p.trace(slog.LevelDebug, "960.10", "IN- : getAcademicYearToken()", inStr)
+
+	start := inStr.indx
+	keyword, err := p.getAlphaToken(inStr)
+	if err != nil {
+		return inStr.errorAt("960.20", start, "When Getting the Academic Year keyword ", ErrInvalidRange, err)
+	}
+	if !strings.EqualFold(keyword, AcademicYearKeyword) {
+		return inStr.errorAt("960.25", start, "Expecting '"+AcademicYearKeyword+"' but finding '"+keyword+"'", ErrInvalidRange, nil)
+	}
+
+	if err = p.skipSpacesDelims(inStr); err != nil {
+		return inStr.errorHere("960.30", "Skipping Spaces searching for the Academic Year ", nil, err)
+	}
+	if inStr.indx >= inStr.len {
+		return inStr.errorHere("960.32", "Missing Academic Year Data ", ErrMissingYear, nil)
+	}
+
+	yearStart := inStr.indx
+	yearStr, err := p.getNumberToken(inStr)
+	if err != nil {
+		return inStr.errorAt("960.35", yearStart, "When Getting the first year of the Academic Year ", ErrInvalidYear, err)
+	}
+	if first, err = p.validateYear(yearStr); err != nil {
+		return inStr.errorAt("960.40", yearStart, "Invalid first year of the Academic Year "+yearStr, nil, err)
+	}
+
+	n := p.rangeSepLen(inStr)
+	if n == 0 {
+		return inStr.errorHere("960.45", "Expecting '-' and the last year of the Academic Year, e.g. "+AcademicYearKeyword+" 2019-20", ErrInvalidRange, nil)
+	}
+	inStr.indx += n
+
+	yearStart = inStr.indx
+	yearStr, err = p.getNumberToken(inStr)
+	if err != nil {
+		return inStr.errorAt("960.50", yearStart, "When Getting the last year of the Academic Year ", ErrInvalidYear, err)
+	}
+	last, _ = strconv.Atoi(yearStr)
+	switch len(yearStr) {
+	case 1, 2:
+		last += first / 100 * 100
+		if last < first {
+			last += 100
+		}
+	case 4:
+	default:
+		return inStr.errorAt("960.52", yearStart, "Invalid last year of the Academic Year "+yearStr, ErrInvalidYear, nil)
+	}
+	if last != first+1 {
+		return inStr.errorAt("960.55", yearStart, "Academic Year "+strconv.Itoa(first)+"-"+yearStr+" must span two consecutive years", ErrInvalidRange, nil)
+	}
+	if !p.Years.Contains(last) {
+		earliest, latest := p.Years.Bounds()
+		return inStr.errorAt("960.60", yearStart, "Invalid Year Range "+strconv.Itoa(last)+" (valid "+strconv.Itoa(earliest)+" - "+strconv.Itoa(latest)+")", ErrYearOutOfRange, nil).with("year", strconv.Itoa(last), "earliest", strconv.Itoa(earliest), "latest", strconv.Itoa(latest))
+	}
+
+	// Fall 2019 - Summer 2020, or a calendar year when it starts with the first term
+	semesters := p.Semesters.Terms()
+	pos := p.termIndex(Term{Semester: p.AcademicYearStart})
+	if pos < 0 {
+		return inStr.panicHere("960.70", "Academic Year start term '"+p.AcademicYearStart+"' not in the Semester Lookup Dictionary")
+	}
+	r := &TermRange{Start: Term{p.AcademicYearStart, first}, End: Term{semesters[len(semesters)-1], first}}
+	if pos > 0 {
+		r.End = Term{semesters[pos-1], last}
+	}
+	r.Terms = p.expandRange(r.Start, r.End)
+	sel.Semester, sel.Year, sel.Range = r.Start.Semester, r.Start.Year, r
+
+	p.trace(slog.LevelDebug, "960.90", "OUT : getAcademicYearToken()", inStr, "start", r.Start.String(), "end", r.End.String())
+
+	return nil
+}
+
+// Opens a term range after the range separator: the term parsed so far is
+// its start, the [OfferSession] tokens from the cursor on are its end (see
+// finishRange)
+func (p *Parser) openRange(inStr *ChStr, sel *CourseSelection) {
+	sel.Range = &TermRange{Start: Term{sel.Semester, sel.Year}, endAt: inStr.indx}
+	sel.Semester, sel.Year = "", 0
+}
+
+// Completes the term range once its end term is parsed, checking the start
+// precedes the end. A range running backwards is reported at its end term.
+//
+//funcid:985
+func (p *Parser) finishRange(inStr *ChStr, sel *CourseSelection) error {
+
+	p.trace(slog.LevelDebug, "985.10", "IN- : finishRange()", nil, "start", sel.Range.Start.String(), "end", sel.Term().String())
+
+	r := sel.Range
+	r.End = Term{sel.Semester, sel.Year}
+	sel.Semester, sel.Year = r.Start.Semester, r.Start.Year
+
+	if p.termIndex(r.Start) >= p.termIndex(r.End) {
+		pe := inStr.errorAt("985.20", r.endAt, "Term range must run forward, "+r.Start.String()+" is not before "+r.End.String(), ErrInvalidRange, nil)
+		return pe.with("start", r.Start.String(), "end", r.End.String())
+	}
+	r.Terms = p.expandRange(r.Start, r.End)
+
+	p.trace(slog.LevelDebug, "985.90", "OUT : finishRange()", nil, "terms", len(r.Terms))
+	return nil
+}
@@ -0,0 +1,86 @@
+package courseparser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		input      string
+		start, end string
+		terms      int
+	}{
+		{"CS 111 Fall 2019 - Spring 2020", "Fall 2019", "Spring 2020", 3},
+		{"CS 111 F19-S21", "Fall 2019", "Spring 2021", 7},
+		{"CS 111 Fall 2019 – Summer 2020", "Fall 2019", "Summer 2020", 4},
+		{"Fall 2019 - Spring 2020 CS 111", "Fall 2019", "Spring 2020", 3},
+		{"CS 111 AY 2019-20", "Fall 2019", "Summer 2020", 4},
+		{"CS 111 ay 2019 - 2020", "Fall 2019", "Summer 2020", 4},
+	}
+
+	p := testParser()
+	for _, test := range tests {
+		sel, err := p.Parse(test.input)
+		if err != nil || sel.Range == nil {
+			t.Errorf("%q: %v %v, want a range", test.input, sel.Tokens(), err)
+			continue
+		}
+		r := sel.Range
+		if r.Start.String() != test.start || r.End.String() != test.end || len(r.Terms) != test.terms || sel.Term() != r.Start {
+			t.Errorf("%q: %v - %v (%d terms, selection %v), want %s - %s (%d terms)", test.input, r.Start, r.End, len(r.Terms), sel.Term(), test.start, test.end, test.terms)
+		}
+	}
+}
+
+func TestParseRangeErrors(t *testing.T) {
+	tests := []struct {
+		input  string
+		kind   error
+		code   string
+		offset int
+	}{
+		// a reversed range is reported at its end term
+		{"CS 111 Spring 2020 - Fall 2019", ErrInvalidRange, "985.20", 21},
+		{"CS 111 Fall 2019-Fall 2019", ErrInvalidRange, "985.20", 17},
+		{"Fall 2020 - Spring 2019 CS 111", ErrInvalidRange, "985.20", 12},
+		// both ends must be inside the year window
+		{"CS 111 Spring 2020 - Fall 2031", ErrYearOutOfRange, "920.35", 26},
+		{"CS 111 Fall 2009 - Spring 2011", ErrYearOutOfRange, "920.35", 12},
+		// an academic year spans two consecutive years inside the window
+		{"CS 111 AY 2019-21", ErrInvalidRange, "960.55", 15},
+		{"CS 111 AY 2019-18", ErrInvalidRange, "960.55", 15},
+		{"CS 111 AY 2019-202", ErrInvalidYear, "960.52", 15},
+		{"CS 111 AY 2019-x", ErrInvalidYear, "650.30", 15},
+		{"CS 111 AY 2030-31", ErrYearOutOfRange, "960.60", 15},
+		{"CS 111 AY 2019", ErrInvalidRange, "960.45", 14},
+	}
+
+	p := testParser()
+	for _, test := range tests {
+		_, err := p.Parse(test.input)
+		pe := Locate(err)
+		if !errors.Is(err, test.kind) || pe == nil || pe.Code != test.code || pe.Offset != test.offset {
+			t.Errorf("%q: error %v at %+v, want %v code %s at %d", test.input, err, pe, test.kind, test.code, test.offset)
+		}
+	}
+}
+
+func TestExpandRange(t *testing.T) {
+	p := testParser()
+
+	terms := p.ExpandRange(Term{"Fall", 2019}, Term{"Fall", 2020})
+	want := []string{"Fall 2019", "Winter 2020", "Spring 2020", "Summer 2020", "Fall 2020"}
+	if len(terms) != len(want) {
+		t.Fatalf("ExpandRange = %v, want %v", terms, want)
+	}
+	for i, term := range terms {
+		if term.String() != want[i] {
+			t.Errorf("term %d = %v, want %s", i, term, want[i])
+		}
+	}
+
+	if terms := p.ExpandRange(Term{"Spring", 2020}, Term{"Fall", 2019}); len(terms) != 0 {
+		t.Errorf("reversed ExpandRange = %v, want none", terms)
+	}
+}
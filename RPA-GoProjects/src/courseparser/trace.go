@@ -0,0 +1,46 @@
+package courseparser
+
+import (
+	"context"
+	"log/slog"
+)
+
+//===========================================================
+//============ funcid Trace =================================
+//===========================================================
+// Every function traces its entry (IN-), exit (OUT) and decisions (MID)
+// under its funcid to Parser.Logger, as structured records with the
+// attributes
+//
+//   funcid : e.g. "950.10"
+//   indx   : rune index of the cursor into the normalized input
+//   offset : rune offset of the cursor into the input as typed
+//   rest   : the input as typed from the cursor
+//
+// plus the tokens the function produced. The levels follow the Code Outline
+//
+//   slog.LevelInfo  : Parse() and the ambiguity resolution (L0)
+//   slog.LevelDebug : layouts, rules, Fields, tokens and validators (L0.5 - L3)
+//   LevelTrace      : the character level primitives (L4, L-1)
+//
+// A nil Logger (the default) turns the trace off.
+
+// LevelTrace is the slog level of the character level primitives
+const LevelTrace = slog.LevelDebug - 4
+
+// Logs one funcid trace record, with the cursor position when inStr is set.
+// In explain mode every record is also kept as an Event of the current Step.
+func (p *Parser) trace(level slog.Level, funcid string, msg string, inStr *ChStr, args ...any) {
+	if p.explain != nil {
+		p.explain.event(funcid, msg, inStr, args)
+	}
+	if p.Logger == nil || !p.Logger.Enabled(context.Background(), level) {
+		return
+	}
+
+	attrs := []any{slog.String("funcid", funcid)}
+	if inStr != nil {
+		attrs = append(attrs, slog.Int("indx", inStr.indx), slog.Int("offset", inStr.srcOffset(inStr.indx)), slog.String("rest", inStr.rest(inStr.indx)))
+	}
+	p.Logger.Log(context.Background(), level, msg, append(attrs, args...)...)
+}
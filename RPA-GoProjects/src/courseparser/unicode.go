@@ -0,0 +1,105 @@
+package courseparser
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+//===========================================================
+//============ Unicode Input Normalization ==================
+//===========================================================
+// The scanner works on runes of the NFKC normalized input (Unicode
+// normalization form KC, by golang.org/x/text/unicode/norm), so full-width
+// IME input ("ＣＳ１１１ Ｆａｌｌ ２０１９"), half-width katakana, superscript
+// digits, no-break and ideographic spaces, ligatures and decomposed accents
+// ("e" + U+0301) parse like their plain forms. A combining mark NFKC has no
+// precomposed letter for stays a part of the letter before it (see isLetter).
+// Error offsets are rune offsets into the input as typed.
+
+// Builds the CH String for input, normalizing it segment by segment so every
+// normalized rune maps back to the first rune of the input it came from
+func newChStr(input string) ChStr {
+	var it norm.Iter
+	var offset int // rune offset of the segment into the input as typed
+
+	inStr := ChStr{src: []rune(input)}
+
+	it.InitString(norm.NFKC, input)
+	for !it.Done() {
+		start := it.Pos()
+		for _, c := range string(it.Next()) {
+			inStr.data = append(inStr.data, c)
+			inStr.orig = append(inStr.orig, offset)
+		}
+		offset += utf8.RuneCountInString(input[start:it.Pos()])
+	}
+	inStr.orig = append(inStr.orig, len(inStr.src))
+	inStr.len = len(inStr.data)
+	return inStr
+}
+
+// String renders the CH String for the trace
+func (inStr ChStr) String() string {
+	return fmt.Sprintf("{%s %d %d}", string(inStr.data), inStr.indx, inStr.len)
+}
+
+// Returns the input as typed from rune index "indx" of the normalized data
+func (inStr *ChStr) rest(indx int) string {
+	return string(inStr.src[inStr.srcOffset(indx):])
+}
+
+// Maps a rune index of the normalized data to a rune offset into the input as typed
+func (inStr *ChStr) srcOffset(indx int) int {
+	if indx < 0 {
+		return indx
+	}
+	if indx >= len(inStr.orig) {
+		return len(inStr.src)
+	}
+	return inStr.orig[indx]
+}
+
+// Maps a rune onto its NFKC form when that is a single rune, e.g. the
+// full-width "，" onto ','
+func foldRune(r rune) rune {
+	folded := norm.NFKC.String(string(r))
+	if c, size := utf8.DecodeRuneInString(folded); size == len(folded) {
+		return c
+	}
+	return r
+}
+
+// Column returns the terminal column of rune offset "offset" in text, where
+// East Asian wide characters take two columns and combining marks none. It
+// is used to point a caret at an error position.
+func Column(text string, offset int) int {
+	var col int
+
+	for i, r := range []rune(text) {
+		if i >= offset {
+			break
+		}
+		col += runeWidth(r)
+	}
+	return col
+}
+
+func runeWidth(r rune) int {
+	switch {
+	case unicode.Is(unicode.Mn, r) || r == '\u200B':
+		return 0
+	case r >= 0x1100 && (r <= 0x115F ||
+		r >= 0x2E80 && r <= 0xA4CF && r != 0x303F ||
+		r >= 0xAC00 && r <= 0xD7A3 ||
+		r >= 0xF900 && r <= 0xFAFF ||
+		r >= 0xFE30 && r <= 0xFE4F ||
+		r >= 0xFF00 && r <= 0xFF60 ||
+		r >= 0xFFE0 && r <= 0xFFE6 ||
+		r >= 0x20000 && r <= 0x3FFFD):
+		return 2
+	}
+	return 1
+}
@@ -0,0 +1,142 @@
+package courseparser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseNormalized(t *testing.T) {
+	tests := []struct {
+		input  string
+		tokens string // "" for an invalid semester
+	}{
+		{"ＣＳ１１１ Ｆａｌｌ ２０１９", "CS 111 2019 Fall"},            // full-width
+		{"ＣＳ－１１１　Ｆａｌｌ２０１９", "CS 111 2019 Fall"},            // full-width hyphen, ideographic space
+		{"CS\u00a0111\u2007Fall 2019", "CS 111 2019 Fall"}, // no-break and figure spaces
+		{"CS 111 Fall ２⁰¹⁹", "CS 111 2019 Fall"},           // superscript digits
+		{"ｶﾞｸ 111 Fall 2019", "ガク 111 2019 Fall"},          // half-width katakana
+		{"CS 111 \ufb01 2019", ""},                         // the "fi" ligature is "fi", not a semester
+	}
+
+	p := testParser()
+	for _, test := range tests {
+		sel, err := p.Parse(test.input)
+		if test.tokens == "" {
+			if !errors.Is(err, ErrInvalidSemester) {
+				t.Errorf("%q: error %v, want %v", test.input, err, ErrInvalidSemester)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", test.input, err)
+			continue
+		}
+		if got := joinTokens(sel.Tokens()); got != test.tokens {
+			t.Errorf("%q: %q, want %q", test.input, got, test.tokens)
+		}
+	}
+}
+
+// Any Unicode space is a space, and counts once as a separator
+func TestParseWhitespace(t *testing.T) {
+	tests := []struct {
+		input  string
+		kind   error // nil when the input parses
+		offset int
+		column int
+	}{
+		{"CS\t111\tFall\t2019", nil, 0, 0},
+		{"\u3000CS 111 Fall 2019\u3000", nil, 0, 0},
+		{"CS\u2009111\u202fFall\u205f2019", nil, 0, 0},
+		{"CS 111 Fall\u00a02019\u00a0", nil, 0, 0},
+		{"CS 111\u3000\u3000Fall 2019", ErrExtraDelimiter, 7, 8},
+		{"CS\u3000111\u3000Fxll 2019", ErrInvalidSemester, 7, 9},
+		{"\u3000\u3000CS 111 Fxll 2019", ErrInvalidSemester, 9, 11},
+		{"CS 111\u3000Fall\u30002019 x", ErrTrailingData, 17, 19},
+		{"CS\u200b111 Fall 2019", ErrInvalidCharacter, 2, 2}, // a zero width space is not a space
+	}
+
+	p := testParser()
+	for _, test := range tests {
+		sel, err := p.Parse(test.input)
+		if test.kind == nil {
+			if err != nil || joinTokens(sel.Tokens()) != "CS 111 2019 Fall" {
+				t.Errorf("%q: %v %v", test.input, sel.Tokens(), err)
+			}
+			continue
+		}
+		pe := Locate(err)
+		if !errors.Is(err, test.kind) || pe == nil || pe.Offset != test.offset || pe.Column != test.column {
+			t.Errorf("%q: error %v at %+v, want %v at offset %d column %d", test.input, err, pe, test.kind, test.offset, test.column)
+		}
+	}
+}
+
+// Combining marks compose with their letter, or else stay a part of it
+func TestParseCombiningMarks(t *testing.T) {
+	p := testParser()
+	p.Locale = "fr"
+
+	sel, err := p.Parse("C\u0301S 111 Fall 2019")
+	if err != nil || sel.Dept != "\u0106S" {
+		t.Errorf("decomposed \u0106S: %v %v", sel.Tokens(), err)
+	}
+	sel, err = p.Parse("CS 111 E\u0301te\u0301 2020")
+	if err != nil || sel.Semester != "Summer" {
+		t.Errorf("decomposed \u00c9t\u00e9: %v %v", sel.Tokens(), err)
+	}
+	for _, input := range []string{"CS 111 Fa\u0301ll 2019", "CS 111 Fǫll 2019", "CS 111 Fx̸ll 2019"} {
+		if _, err := p.Parse(input); !errors.Is(err, ErrInvalidSemester) {
+			t.Errorf("%q: error %v, want %v", input, err, ErrInvalidSemester)
+		}
+	}
+}
+
+// Error offsets are rune offsets into the input as typed, and columns count
+// East Asian wide characters twice
+func TestErrorOffsets(t *testing.T) {
+	tests := []struct {
+		input  string
+		offset int
+		column int
+		char   string
+	}{
+		{"CS 111 Fxll 2019", 7, 7, "F"},
+		{"ＣＳ１１１ Ｆｘｌｌ ２０１９", 6, 11, "Ｆ"},
+		{"Ｃ\u0301S 111 Fxll 2019", 8, 8, "F"},
+		{"ＣＳ#１１１ Fall 2019", 2, 4, "#"},
+		{"CS 111 Fall ２０１９x", 16, 20, "x"},
+		{"CS \ufb00111 Fall 2019", 3, 3, ""},
+	}
+
+	p := testParser()
+	for _, test := range tests {
+		_, err := p.Parse(test.input)
+		pe := Locate(err)
+		if pe == nil {
+			t.Errorf("%q: error %v, want one at %d", test.input, err, test.offset)
+			continue
+		}
+		if pe.Offset != test.offset || pe.Column != test.column || (test.char != "" && pe.Char != []rune(test.char)[0]) {
+			t.Errorf("%q: offset %d column %d char %q, want %d %d %q", test.input, pe.Offset, pe.Column, pe.Char, test.offset, test.column, test.char)
+		}
+	}
+}
+
+func TestColumn(t *testing.T) {
+	tests := []struct {
+		text   string
+		offset int
+		column int
+	}{
+		{"CS 111", 3, 3},
+		{"ＣＳ１１１", 3, 6},
+		{"Été 2020", 5, 3},
+		{"ガク 111", 3, 5},
+	}
+	for _, test := range tests {
+		if got := Column(test.text, test.offset); got != test.column {
+			t.Errorf("Column(%q, %d) = %d, want %d", test.text, test.offset, got, test.column)
+		}
+	}
+}
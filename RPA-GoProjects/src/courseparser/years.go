@@ -0,0 +1,83 @@
+package courseparser
+
+import (
+	"time"
+)
+
+//===========================================================
+//============ Academic Year Window =========================
+//===========================================================
+
+// YearWindow is the range of years validateYear() accepts for a [Year]
+// token, either fixed (Earliest - Latest) or sliding relative to the current
+// year (Back years back, Ahead years ahead). Both ends are inclusive.
+type YearWindow struct {
+	Earliest int
+	Latest   int
+
+	Relative bool
+	Back     int
+	Ahead    int
+
+	// Pivot for 2 digit years. An abbreviated year below Pivot is 20yy, any
+	// other is 19yy, e.g. with Pivot 50 "49" is 2049 and "98" is 1998.
+	// A Pivot of 0 (or >= 100) normalizes every 2 digit year to the 21st century.
+	Pivot int
+
+	// Clock returns the current time for a Relative window, nil means time.Now
+	Clock func() time.Time
+}
+
+// DefaultYearWindow accepts years from 15 years back to 2 years ahead of the
+// current year, and normalizes 2 digit years to the 21st century
+func DefaultYearWindow() YearWindow {
+	return RelativeYearWindow(15, 2)
+}
+
+// FixedYearWindow accepts the years earliest to latest
+func FixedYearWindow(earliest, latest int) YearWindow {
+	return YearWindow{Earliest: earliest, Latest: latest}
+}
+
+// RelativeYearWindow accepts the years from back years before to ahead
+// years after the current year
+func RelativeYearWindow(back, ahead int) YearWindow {
+	return YearWindow{Relative: true, Back: back, Ahead: ahead}
+}
+
+// Bounds returns the earliest and latest valid year
+func (w YearWindow) Bounds() (int, int) {
+	if !w.Relative {
+		return w.Earliest, w.Latest
+	}
+
+	now := time.Now
+	if w.Clock != nil {
+		now = w.Clock
+	}
+	year := now().Year()
+	return year - w.Back, year + w.Ahead
+}
+
+// Reports whether the window has bounds, fixed or relative. A window with
+// only a Pivot or a Clock set has none.
+func (w YearWindow) bounded() bool {
+	return w.Relative || w.Earliest != 0 || w.Latest != 0
+}
+
+// Contains reports whether year is inside the window
+func (w YearWindow) Contains(year int) bool {
+	earliest, latest := w.Bounds()
+	return year >= earliest && year <= latest
+}
+
+// Expand normalizes an abbreviated year of "digits" digits around Pivot
+func (w YearWindow) Expand(year int, digits int) int {
+	if digits > 2 || year < 0 || year > 99 {
+		return year
+	}
+	if w.Pivot > 0 && w.Pivot < 100 && year >= w.Pivot {
+		return 1900 + year
+	}
+	return 2000 + year
+}
@@ -0,0 +1,72 @@
+package courseparser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestYearWindow(t *testing.T) {
+	clock := func() time.Time { return time.Date(2019, 9, 1, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		window YearWindow
+		year   int
+		want   bool
+	}{
+		{YearWindow{Relative: true, Back: 15, Ahead: 2, Clock: clock}, 2004, true},
+		{YearWindow{Relative: true, Back: 15, Ahead: 2, Clock: clock}, 2003, false},
+		{YearWindow{Relative: true, Back: 15, Ahead: 2, Clock: clock}, 2021, true},
+		{YearWindow{Relative: true, Back: 15, Ahead: 2, Clock: clock}, 2022, false},
+		{YearWindow{Relative: true, Clock: clock}, 2019, true},
+		{YearWindow{Relative: true, Clock: clock}, 2018, false},
+		{FixedYearWindow(2007, 2021), 2007, true},
+		{FixedYearWindow(2007, 2021), 2021, true},
+		{FixedYearWindow(2007, 2021), 2006, false},
+		{FixedYearWindow(2007, 2021), 2022, false},
+	}
+	for _, test := range tests {
+		if got := test.window.Contains(test.year); got != test.want {
+			earliest, latest := test.window.Bounds()
+			t.Errorf("%d - %d: Contains(%d) = %v, want %v", earliest, latest, test.year, got, test.want)
+		}
+	}
+}
+
+func TestYearWindowExpand(t *testing.T) {
+	tests := []struct {
+		pivot, year, digits, want int
+	}{
+		{0, 19, 2, 2019},
+		{0, 98, 2, 2098},
+		{50, 49, 2, 2049},
+		{50, 50, 2, 1950},
+		{50, 98, 2, 1998},
+		{100, 98, 2, 2098},
+		{50, 1998, 4, 1998},
+	}
+	for _, test := range tests {
+		if got := (YearWindow{Pivot: test.pivot}).Expand(test.year, test.digits); got != test.want {
+			t.Errorf("Pivot %d: Expand(%d, %d) = %d, want %d", test.pivot, test.year, test.digits, got, test.want)
+		}
+	}
+}
+
+// The relative window follows the Parser's Clock at its edges
+func TestParseRelativeWindow(t *testing.T) {
+	p := New()
+	p.Years.Clock = func() time.Time { return time.Date(2019, 9, 1, 0, 0, 0, 0, time.UTC) }
+
+	for _, test := range []struct {
+		input string
+		ok    bool
+	}{
+		{"CS 111 Fall 2004", true},
+		{"CS 111 Fall 2003", false},
+		{"CS 111 Fall 2021", true},
+		{"CS 111 Fall 2022", false},
+	} {
+		if _, err := p.Parse(test.input); (err == nil) != test.ok {
+			t.Errorf("%q: error %v, want ok %v", test.input, err, test.ok)
+		}
+	}
+}